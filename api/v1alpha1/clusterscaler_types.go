@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterScalerSpec defines the desired state of ClusterScaler
+type ClusterScalerSpec struct {
+	// NamespaceSelector selects the namespaces the schedule applies to. An
+	// empty selector matches every namespace.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// Target selects the workloads inside the matched namespaces.
+	// +optional
+	Target ScalerTarget `json:"target,omitempty"`
+
+	ScheduleSpec `json:",inline"`
+}
+
+// ClusterScalerStatus defines the observed state of ClusterScaler
+type ClusterScalerStatus struct {
+	// ObservedGeneration is the generation the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// MatchedNamespaces is the number of namespaces the NamespaceSelector
+	// matches.
+	// +optional
+	MatchedNamespaces int32 `json:"matchedNamespaces"`
+
+	// MatchedTargets is the number of workloads the ClusterScaler selects
+	// across those namespaces.
+	// +optional
+	MatchedTargets int32 `json:"matchedTargets"`
+
+	// Conditions are Ready and InvalidSchedule.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Uptime",type=string,JSONPath=`.spec.uptime`
+// +kubebuilder:printcolumn:name="Downtime",type=string,JSONPath=`.spec.downtime`
+// +kubebuilder:printcolumn:name="Namespaces",type=integer,JSONPath=`.status.matchedNamespaces`
+// +kubebuilder:printcolumn:name="Targets",type=integer,JSONPath=`.status.matchedTargets`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterScaler is the Schema for the clusterscalers API
+type ClusterScaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterScalerSpec   `json:"spec,omitempty"`
+	Status ClusterScalerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterScalerList contains a list of ClusterScaler
+type ClusterScalerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterScaler `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterScaler{}, &ClusterScalerList{})
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CalendarAction is the state a calendar exception forces.
+// +kubebuilder:validation:Enum=Down;Up
+type CalendarAction string
+
+const (
+	// CalendarActionDown forces downtime, whatever the uptime schedule says.
+	CalendarActionDown CalendarAction = "Down"
+	// CalendarActionUp forces uptime, whatever the downtime schedule says.
+	CalendarActionUp CalendarAction = "Up"
+)
+
+// CalendarException is a dated exception to the weekly schedule.
+type CalendarException struct {
+	// Description of the exception, e.g. "Company shutdown".
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Date is the first day of the exception, formatted YYYY-MM-DD.
+	// +kubebuilder:validation:Pattern=`^\d{4}-\d{2}-\d{2}$`
+	Date string `json:"date"`
+
+	// EndDate is the last day of the exception, inclusive. Defaults to Date.
+	// +kubebuilder:validation:Pattern=`^\d{4}-\d{2}-\d{2}$`
+	// +optional
+	EndDate string `json:"endDate,omitempty"`
+
+	// Window restricts the exception to a time of day on each of its days,
+	// e.g. "08:00-12:00". The exception covers whole days when empty.
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// Action is the state forced during the exception.
+	// +kubebuilder:default=Down
+	// +optional
+	Action CalendarAction `json:"action,omitempty"`
+}
+
+// ConfigMapKeyReference selects a key of a ConfigMap.
+type ConfigMapKeyReference struct {
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Key holding the document.
+	Key string `json:"key"`
+}
+
+// CalendarImport is an iCalendar (.ics) document whose events, including
+// recurring ones, force a state. Exactly one of ConfigMapKeyRef and Path
+// must be set.
+type CalendarImport struct {
+	// ConfigMapKeyRef selects the ConfigMap key holding the document.
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeyReference `json:"configMapKeyRef,omitempty"`
+
+	// Path is a file holding the document, relative to the directory set
+	// with the controller's --calendar-import-dir flag. Absolute paths must
+	// be within that directory.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Action is the state forced during the events.
+	// +kubebuilder:default=Down
+	// +optional
+	Action CalendarAction `json:"action,omitempty"`
+}
+
+// ScaleCalendarSpec defines the desired state of ScaleCalendar
+type ScaleCalendarSpec struct {
+	// TimeZone is the IANA zone dates and windows are expressed in.
+	// Defaults to the controller's --default-timezone, UTC unless set.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Holidays lists ISO 3166 country codes whose national public holidays
+	// force whole-day downtime, e.g. ["FR", "DE"].
+	// +optional
+	Holidays []string `json:"holidays,omitempty"`
+
+	// Exceptions lists dated exceptions. They take priority over imports and
+	// holidays.
+	// +optional
+	Exceptions []CalendarException `json:"exceptions,omitempty"`
+
+	// Imports lists iCalendar documents, such as company closures. Their
+	// events take priority over holidays. Floating times and all-day events
+	// are read in TimeZone.
+	// +optional
+	Imports []CalendarImport `json:"imports,omitempty"`
+}
+
+// ScaleCalendarStatus defines the observed state of ScaleCalendar
+type ScaleCalendarStatus struct {
+	// ObservedGeneration is the generation the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Action is the state the calendar currently forces, empty when no
+	// exception, imported event or holiday is running.
+	// +optional
+	Action CalendarAction `json:"action,omitempty"`
+
+	// NextTransitionTime is when the calendar next starts, stops or changes
+	// the state it forces, if that happens within the next year.
+	// +optional
+	NextTransitionTime *metav1.Time `json:"nextTransitionTime,omitempty"`
+
+	// Conditions are Ready, False when the calendar or one of its imports
+	// cannot be read.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="TimeZone",type=string,JSONPath=`.spec.timeZone`
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.status.action`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Next Transition",type=string,JSONPath=`.status.nextTransitionTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ScaleCalendar is the Schema for the scalecalendars API
+type ScaleCalendar struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScaleCalendarSpec   `json:"spec,omitempty"`
+	Status ScaleCalendarStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScaleCalendarList contains a list of ScaleCalendar
+type ScaleCalendarList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScaleCalendar `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScaleCalendar{}, &ScaleCalendarList{})
+}
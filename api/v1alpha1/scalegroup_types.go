@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionInvalidGroup is True when the members of a ScaleGroup reference
+// unknown members or form a cycle.
+const ConditionInvalidGroup = "InvalidGroup"
+
+// ScaleGroupMember is a workload of a ScaleGroup.
+type ScaleGroupMember struct {
+	TargetReference `json:",inline"`
+
+	// DependsOn lists the members this one needs, by name or, when several
+	// members share a name, by Kind/name. They are woken up and ready before
+	// it, and put to sleep after it.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// ScaleGroupSpec defines the desired state of ScaleGroup
+type ScaleGroupSpec struct {
+	// Members lists the workloads of the group, in the ScaleGroup namespace.
+	// Members must be unique by kind and name.
+	// +kubebuilder:validation:MinItems=1
+	Members []ScaleGroupMember `json:"members"`
+}
+
+// ScaleGroupMemberStatus is the observed state of a ScaleGroup member.
+type ScaleGroupMemberStatus struct {
+	// Name of the member, as Kind/name.
+	Name string `json:"name"`
+
+	// Phase is the state the member is kept in.
+	// +optional
+	Phase TargetPhase `json:"phase,omitempty"`
+
+	// Waiting explains why the member is held back, if it is.
+	// +optional
+	Waiting string `json:"waiting,omitempty"`
+}
+
+// ScaleGroupStatus defines the observed state of ScaleGroup
+type ScaleGroupStatus struct {
+	// ObservedGeneration is the generation the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Order lists the members tier by tier in wake-up order, members of a
+	// tier separated by commas. Sleep happens in reverse.
+	// +optional
+	Order []string `json:"order,omitempty"`
+
+	// Members lists the state of each member.
+	// +optional
+	Members []ScaleGroupMemberStatus `json:"members,omitempty"`
+
+	// Conditions are Ready and InvalidGroup.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Order",type=string,JSONPath=`.status.order`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ScaleGroup is the Schema for the scalegroups API
+type ScaleGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScaleGroupSpec   `json:"spec,omitempty"`
+	Status ScaleGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScaleGroupList contains a list of ScaleGroup
+type ScaleGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScaleGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScaleGroup{}, &ScaleGroupList{})
+}
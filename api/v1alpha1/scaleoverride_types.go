@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OverrideState is the state a ScaleOverride forces on its targets.
+// +kubebuilder:validation:Enum=Up;Down;Excluded
+type OverrideState string
+
+const (
+	// OverrideUp keeps targets running, whatever their schedule says.
+	OverrideUp OverrideState = "Up"
+	// OverrideDown keeps targets scaled down, whatever their schedule says.
+	OverrideDown OverrideState = "Down"
+	// OverrideExcluded leaves targets untouched, like kubescale/exclude-until.
+	OverrideExcluded OverrideState = "Excluded"
+)
+
+// OverridePhase is the lifecycle phase of a ScaleOverride.
+type OverridePhase string
+
+const (
+	// OverridePhaseActive means the state is forced on the targets.
+	OverridePhaseActive OverridePhase = "Active"
+	// OverridePhaseExpired means ExpiresAt has passed and the override is
+	// being deleted.
+	OverridePhaseExpired OverridePhase = "Expired"
+	// OverridePhaseInvalid means the spec is rejected and nothing is forced.
+	OverridePhaseInvalid OverridePhase = "Invalid"
+)
+
+// TargetReference names a single workload.
+type TargetReference struct {
+	// Kind of the workload.
+	Kind TargetKind `json:"kind"`
+
+	// Name of the workload.
+	Name string `json:"name"`
+}
+
+// ScaleOverrideSpec defines the desired state of ScaleOverride
+type ScaleOverrideSpec struct {
+	// TargetRef names the workload to override. Exactly one of TargetRef and
+	// Target must be set.
+	// +optional
+	TargetRef *TargetReference `json:"targetRef,omitempty"`
+
+	// Target selects the workloads to override in the ScaleOverride namespace.
+	// +optional
+	Target *ScalerTarget `json:"target,omitempty"`
+
+	// State is forced on the targets until ExpiresAt.
+	State OverrideState `json:"state"`
+
+	// ExpiresAt is when the override stops applying. Expired overrides are
+	// deleted by the controller.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+
+	// Reason explains why the override is needed.
+	// +kubebuilder:validation:MinLength=1
+	Reason string `json:"reason"`
+
+	// Requester identifies who asked for the override.
+	// +optional
+	Requester string `json:"requester,omitempty"`
+}
+
+// ScaleOverrideStatus defines the observed state of ScaleOverride
+type ScaleOverrideStatus struct {
+	// ObservedGeneration is the generation the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase is Active, Expired or Invalid.
+	// +optional
+	Phase OverridePhase `json:"phase,omitempty"`
+
+	// Message explains an Invalid phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// MatchedTargets is the number of workloads the override applies to.
+	// +optional
+	MatchedTargets int32 `json:"matchedTargets"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.spec.state`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Targets",type=integer,JSONPath=`.status.matchedTargets`
+// +kubebuilder:printcolumn:name="Expires",type=string,format=date-time,JSONPath=`.spec.expiresAt`
+// +kubebuilder:printcolumn:name="Requester",type=string,JSONPath=`.spec.requester`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.spec.reason`,priority=1
+
+// ScaleOverride is the Schema for the scaleoverrides API
+type ScaleOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScaleOverrideSpec   `json:"spec,omitempty"`
+	Status ScaleOverrideStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScaleOverrideList contains a list of ScaleOverride
+type ScaleOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScaleOverride `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScaleOverride{}, &ScaleOverrideList{})
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolicyEnforcement is what a ScalePolicy does about a violation.
+// +kubebuilder:validation:Enum=Report;Clamp
+type PolicyEnforcement string
+
+const (
+	// PolicyReport only lists violations in the ScalePolicy status.
+	PolicyReport PolicyEnforcement = "Report"
+	// PolicyClamp also makes the controller ignore the offending exclusions
+	// and force the required downtime.
+	PolicyClamp PolicyEnforcement = "Clamp"
+)
+
+// Rules a ScalePolicy checks.
+const (
+	RuleExcludeNotAllowed   = "ExcludeNotAllowed"
+	RuleExcludeUntilHorizon = "ExcludeUntilHorizon"
+	RuleMaxWeeklyUptime     = "MaxWeeklyUptime"
+	RuleRequiredDowntime    = "RequiredDowntime"
+)
+
+// ConditionShadowed is True when some workloads a ScalePolicy selects fall
+// under an earlier policy by name, which applies to them instead.
+const ConditionShadowed = "Shadowed"
+
+// ScalePolicySpec defines the desired state of ScalePolicy
+type ScalePolicySpec struct {
+	// NamespaceSelector chooses the namespaces the policy applies to. An
+	// empty selector matches every namespace.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// MaxWeeklyUptimeHours is the most hours per week a workload may be
+	// scheduled up. It is only reported, never clamped.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=168
+	// +optional
+	MaxWeeklyUptimeHours *int32 `json:"maxWeeklyUptimeHours,omitempty"`
+
+	// MaxExcludeUntil is the furthest in the future kubescale/exclude-until
+	// may point, e.g. "168h", "7d" or "P1W".
+	// +optional
+	MaxExcludeUntil string `json:"maxExcludeUntil,omitempty"`
+
+	// AllowExclude tells whether kubescale/exclude and Excluded overrides
+	// may be used.
+	// +kubebuilder:default=true
+	// +optional
+	AllowExclude *bool `json:"allowExclude,omitempty"`
+
+	// RequiredDowntime lists windows, in the kubescale/downtime syntax,
+	// during which every workload must be down.
+	// +optional
+	RequiredDowntime []string `json:"requiredDowntime,omitempty"`
+
+	// Enforcement is Report to only list violations, or Clamp to also
+	// enforce the policy.
+	// +kubebuilder:default=Report
+	// +optional
+	Enforcement PolicyEnforcement `json:"enforcement,omitempty"`
+}
+
+// PolicyViolation is a workload whose kubescale settings break a rule.
+type PolicyViolation struct {
+	// Namespace of the workload.
+	Namespace string `json:"namespace"`
+
+	// Kind of the workload.
+	Kind TargetKind `json:"kind"`
+
+	// Name of the workload.
+	Name string `json:"name"`
+
+	// Rule is the broken rule.
+	Rule string `json:"rule"`
+
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+// ScalePolicyStatus defines the observed state of ScalePolicy
+type ScalePolicyStatus struct {
+	// ObservedGeneration is the generation the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// MatchedTargets is the number of workloads the policy applies to,
+	// leaving out those an earlier policy applies to.
+	// +optional
+	MatchedTargets int32 `json:"matchedTargets"`
+
+	// ViolationCount is the total number of violations.
+	// +optional
+	ViolationCount int32 `json:"violationCount"`
+
+	// Violations lists the violations, truncated to the first 100.
+	// +optional
+	Violations []PolicyViolation `json:"violations,omitempty"`
+
+	// Conditions are Ready and Shadowed.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Enforcement",type=string,JSONPath=`.spec.enforcement`
+// +kubebuilder:printcolumn:name="Targets",type=integer,JSONPath=`.status.matchedTargets`
+// +kubebuilder:printcolumn:name="Violations",type=integer,JSONPath=`.status.violationCount`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ScalePolicy is the Schema for the scalepolicies API
+type ScalePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScalePolicySpec   `json:"spec,omitempty"`
+	Status ScalePolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScalePolicyList contains a list of ScalePolicy
+type ScalePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScalePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScalePolicy{}, &ScalePolicyList{})
+}
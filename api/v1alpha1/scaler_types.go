@@ -0,0 +1,251 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// TargetKind is a workload kind kubescale knows how to scale.
+// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;CronJob;Prometheus
+type TargetKind string
+
+const (
+	KindDeployment  TargetKind = "Deployment"
+	KindStatefulSet TargetKind = "StatefulSet"
+	KindDaemonSet   TargetKind = "DaemonSet"
+	KindCronJob     TargetKind = "CronJob"
+	KindPrometheus  TargetKind = "Prometheus"
+)
+
+// ReplicaRounding is how a percentage of replicas is rounded to a count.
+// +kubebuilder:validation:Enum=Up;Down;Nearest
+type ReplicaRounding string
+
+const (
+	ReplicaRoundingUp      ReplicaRounding = "Up"
+	ReplicaRoundingDown    ReplicaRounding = "Down"
+	ReplicaRoundingNearest ReplicaRounding = "Nearest"
+)
+
+// ScalerTarget selects the workloads a Scaler applies to.
+type ScalerTarget struct {
+	// Selector matches workloads by label. An empty selector matches every
+	// workload in the namespace.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Kinds restricts the match to the listed workload kinds. All supported
+	// kinds are matched when empty.
+	// +optional
+	Kinds []TargetKind `json:"kinds,omitempty"`
+}
+
+// ScalerExclusion names a workload the Scaler leaves alone even though it
+// matches the target selector.
+type ScalerExclusion struct {
+	// Kind of the excluded workload. Any kind matches when empty.
+	// +optional
+	Kind TargetKind `json:"kind,omitempty"`
+
+	// Name of the excluded workload.
+	Name string `json:"name"`
+}
+
+// ScheduleSpec is the schedule shared by Scaler and ClusterScaler.
+type ScheduleSpec struct {
+	// Uptime is the window during which targets run, using the
+	// kubescale/uptime syntax, e.g. "Mon-Fri 08:00-20:00 Europe/Paris".
+	// +optional
+	Uptime string `json:"uptime,omitempty"`
+
+	// Downtime is the window during which targets are scaled down, using the
+	// kubescale/downtime syntax. It takes priority over Uptime.
+	// +optional
+	Downtime string `json:"downtime,omitempty"`
+
+	// DowntimeReplicas is the replica count kept during downtime, or a
+	// percentage such as "25%" of the count targets had before. Defaults
+	// to 0.
+	// +optional
+	DowntimeReplicas *intstr.IntOrString `json:"downtimeReplicas,omitempty"`
+
+	// ReplicaRounding is how percentages of replicas are rounded to a
+	// count. Defaults to Up.
+	// +optional
+	ReplicaRounding ReplicaRounding `json:"replicaRounding,omitempty"`
+
+	// MinReplicas is the lowest count a percentage of replicas is rounded
+	// to. Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Profiles are the replica counts targets run at during their schedules,
+	// the first active one winning. Outside every profile targets return to
+	// their normal count. Downtime takes priority over Profiles, and
+	// Profiles over Uptime.
+	// +optional
+	Profiles []ReplicaProfile `json:"profiles,omitempty"`
+
+	// Calendar is the name of a ScaleCalendar whose exceptions override
+	// Uptime and Downtime.
+	// +optional
+	Calendar string `json:"calendar,omitempty"`
+}
+
+// ReplicaProfile is a replica count applied during a schedule.
+type ReplicaProfile struct {
+	// Replicas is the replica count of the targets while Schedule is
+	// active, or a percentage such as "50%" of the count they had before.
+	Replicas intstr.IntOrString `json:"replicas"`
+
+	// Schedule is when the profile applies, using the kubescale/uptime
+	// syntax.
+	Schedule string `json:"schedule"`
+}
+
+// ScalerSpec defines the desired state of Scaler
+type ScalerSpec struct {
+	// Target selects the workloads in the Scaler namespace.
+	Target ScalerTarget `json:"target"`
+
+	ScheduleSpec `json:",inline"`
+
+	// Exclusions lists matched workloads this Scaler does not manage.
+	// +optional
+	Exclusions []ScalerExclusion `json:"exclusions,omitempty"`
+}
+
+// TargetPhase is the state kubescale keeps a target in.
+// +kubebuilder:validation:Enum=Up;Down;Excluded;Error
+type TargetPhase string
+
+const (
+	// TargetPhaseUp means the target runs at its normal scale.
+	TargetPhaseUp TargetPhase = "Up"
+	// TargetPhaseDown means the target is scaled down or suspended.
+	TargetPhaseDown TargetPhase = "Down"
+	// TargetPhaseExcluded means the target is left alone by an exclude
+	// annotation or an override.
+	TargetPhaseExcluded TargetPhase = "Excluded"
+	// TargetPhaseError means the target could not be scaled.
+	TargetPhaseError TargetPhase = "Error"
+)
+
+// Condition types reported on Scaler objects.
+const (
+	// ConditionReady is True when the schedule is valid and every matched
+	// target was reconciled.
+	ConditionReady = "Ready"
+	// ConditionInvalidSchedule is True when the schedule cannot be parsed.
+	ConditionInvalidSchedule = "InvalidSchedule"
+	// ConditionExpiredWindows is True when the uptime or downtime holds
+	// dated windows that have already ended and can be removed.
+	ConditionExpiredWindows = "ExpiredWindows"
+)
+
+// TargetStatus is the observed state of one workload matched by a Scaler.
+type TargetStatus struct {
+	// Kind of the workload.
+	Kind TargetKind `json:"kind"`
+
+	// Name of the workload.
+	Name string `json:"name"`
+
+	// Phase is the state the workload is kept in.
+	Phase TargetPhase `json:"phase"`
+
+	// Message explains an Error phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Replicas is the replica count or percentage of the active profile,
+	// if any.
+	// +optional
+	Replicas *intstr.IntOrString `json:"replicas,omitempty"`
+
+	// Waiting explains why the target is held back by its ScaleGroup.
+	// +optional
+	Waiting string `json:"waiting,omitempty"`
+
+	// LastTransitionTime is when Phase last changed.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ScalerStatus defines the observed state of Scaler
+type ScalerStatus struct {
+	// ObservedGeneration is the generation the status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// MatchedTargets is the number of workloads the Scaler selects.
+	// +optional
+	MatchedTargets int32 `json:"matchedTargets"`
+
+	// Targets lists the matched workloads and their phase.
+	// +optional
+	Targets []TargetStatus `json:"targets,omitempty"`
+
+	// LastTransitionTime is when a matched target last changed phase.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// NextTransitionTime is when the schedule next switches between uptime
+	// and downtime, if that happens within the next week.
+	// +optional
+	NextTransitionTime *metav1.Time `json:"nextTransitionTime,omitempty"`
+
+	// Conditions are Ready, InvalidSchedule and ExpiredWindows.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Uptime",type=string,JSONPath=`.spec.uptime`
+// +kubebuilder:printcolumn:name="Downtime",type=string,JSONPath=`.spec.downtime`
+// +kubebuilder:printcolumn:name="Targets",type=integer,JSONPath=`.status.matchedTargets`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Next Transition",type=string,JSONPath=`.status.nextTransitionTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Scaler is the Schema for the scalers API
+type Scaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScalerSpec   `json:"spec,omitempty"`
+	Status ScalerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScalerList contains a list of Scaler
+type ScalerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Scaler `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Scaler{}, &ScalerList{})
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+	"github.com/cicd-toolkit/kubescale/internal/schedule"
+)
+
+// calendarHorizon is how far ahead the status of a ScaleCalendar looks for
+// its next transition, far enough to show the next holiday.
+const calendarHorizon = 366 * 24 * time.Hour
+
+// calendarException is a parsed ScaleCalendar exception. Dates are at
+// midnight UTC; start and end are minutes since midnight.
+type calendarException struct {
+	from, to   time.Time
+	start, end int
+	action     autoscalev1alpha1.CalendarAction
+}
+
+// calendarImport is a parsed ScaleCalendar import.
+type calendarImport struct {
+	events *schedule.Schedule
+	action autoscalev1alpha1.CalendarAction
+}
+
+// calendar is a parsed ScaleCalendar.
+type calendar struct {
+	location   *time.Location
+	holidays   []string
+	exceptions []calendarException
+	imports    []calendarImport
+}
+
+// importReader returns the iCalendar document of an import.
+type importReader func(autoscalev1alpha1.CalendarImport) (string, error)
+
+// newCalendar parses spec, reading its imports with read. A calendar
+// without a timezone uses loc, UTC when nil.
+func newCalendar(spec *autoscalev1alpha1.ScaleCalendarSpec, loc *time.Location, read importReader) (*calendar, error) {
+	cal := &calendar{location: time.UTC}
+	if loc != nil {
+		cal.location = loc
+	}
+	if spec.TimeZone != "" {
+		loc, err := time.LoadLocation(spec.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", spec.TimeZone)
+		}
+		cal.location = loc
+	}
+
+	for _, country := range spec.Holidays {
+		if _, ok := holidayCalendars[strings.ToUpper(country)]; !ok {
+			return nil, fmt.Errorf("no holidays known for country: %s", country)
+		}
+		cal.holidays = append(cal.holidays, country)
+	}
+
+	for i, ex := range spec.Exceptions {
+		parsed, err := parseCalendarException(ex)
+		if err != nil {
+			return nil, fmt.Errorf("exception %d: %w", i, err)
+		}
+		cal.exceptions = append(cal.exceptions, parsed)
+	}
+
+	for i, imp := range spec.Imports {
+		if (imp.ConfigMapKeyRef == nil) == (imp.Path == "") {
+			return nil, fmt.Errorf("import %d: exactly one of configMapKeyRef and path must be set", i)
+		}
+		data, err := read(imp)
+		if err != nil {
+			return nil, fmt.Errorf("import %d: %w", i, err)
+		}
+		events, err := schedule.ParseICS(data, cal.location)
+		if err != nil {
+			return nil, fmt.Errorf("import %d: %w", i, err)
+		}
+		action := imp.Action
+		if action == "" {
+			action = autoscalev1alpha1.CalendarActionDown
+		}
+		cal.imports = append(cal.imports, calendarImport{events: events, action: action})
+	}
+	return cal, nil
+}
+
+func parseCalendarException(ex autoscalev1alpha1.CalendarException) (calendarException, error) {
+	parsed := calendarException{start: 0, end: 24 * 60, action: ex.Action}
+	if parsed.action == "" {
+		parsed.action = autoscalev1alpha1.CalendarActionDown
+	}
+
+	var err error
+	if parsed.from, err = time.Parse(time.DateOnly, ex.Date); err != nil {
+		return parsed, fmt.Errorf("invalid date: %s", ex.Date)
+	}
+	parsed.to = parsed.from
+	if ex.EndDate != "" {
+		if parsed.to, err = time.Parse(time.DateOnly, ex.EndDate); err != nil {
+			return parsed, fmt.Errorf("invalid end date: %s", ex.EndDate)
+		}
+		if parsed.to.Before(parsed.from) {
+			return parsed, fmt.Errorf("end date %s is before date %s", ex.EndDate, ex.Date)
+		}
+	}
+
+	if ex.Window != "" {
+		startStr, endStr, ok := strings.Cut(ex.Window, "-")
+		if !ok {
+			return parsed, fmt.Errorf("invalid window: %s", ex.Window)
+		}
+		start, err := time.Parse("15:04", strings.TrimSpace(startStr))
+		if err != nil {
+			return parsed, fmt.Errorf("invalid window: %s", ex.Window)
+		}
+		end, err := time.Parse("15:04", strings.TrimSpace(endStr))
+		if err != nil {
+			return parsed, fmt.Errorf("invalid window: %s", ex.Window)
+		}
+		parsed.start = start.Hour()*60 + start.Minute()
+		parsed.end = end.Hour()*60 + end.Minute()
+		if parsed.end <= parsed.start {
+			return parsed, fmt.Errorf("window %s must end after it starts", ex.Window)
+		}
+	}
+	return parsed, nil
+}
+
+// action returns the state the calendar forces at t, or "" when t is not
+// covered by any exception, imported event or holiday. Exceptions are checked
+// in order and take priority over imports, which take priority over holidays.
+func (c *calendar) action(t time.Time) autoscalev1alpha1.CalendarAction {
+	y, m, d := t.In(c.location).Date()
+	day := date(y, m, d)
+
+	for _, ex := range c.exceptions {
+		if day.Before(ex.from) || day.After(ex.to) {
+			continue
+		}
+		start := schedule.WallClock(y, m, d, ex.start, c.location)
+		end := schedule.WallClock(y, m, d, ex.end, c.location)
+		if !t.Before(start) && t.Before(end) {
+			return ex.action
+		}
+	}
+
+	for _, imp := range c.imports {
+		if imp.events.Active(t) {
+			return imp.action
+		}
+	}
+
+	for _, country := range c.holidays {
+		if isHoliday(country, day) {
+			return autoscalev1alpha1.CalendarActionDown
+		}
+	}
+	return ""
+}
+
+// next returns the first instant after t at which action may change: the
+// next local midnight, or an exception or imported event boundary before it.
+// A nil calendar never changes.
+func (c *calendar) next(t time.Time) (time.Time, bool) {
+	if c == nil {
+		return time.Time{}, false
+	}
+	y, m, d := t.In(c.location).Date()
+	next := schedule.WallClock(y, m, d+1, 0, c.location)
+	for _, ex := range c.exceptions {
+		for _, minute := range []int{ex.start, ex.end} {
+			if at := schedule.WallClock(y, m, d, minute, c.location); at.After(t) && at.Before(next) {
+				next = at
+			}
+		}
+	}
+	for _, imp := range c.imports {
+		if at, ok := imp.events.NextTransition(t); ok && at.Before(next) {
+			next = at
+		}
+	}
+	return next, true
+}
+
+// nextChange returns when the action forced by the calendar next differs from
+// the one at now, or false when it stays the same within calendarHorizon.
+func (c *calendar) nextChange(now time.Time) (time.Time, bool) {
+	current := c.action(now)
+	for at := now; ; {
+		next, ok := c.next(at)
+		if !ok || next.Sub(now) > calendarHorizon {
+			return time.Time{}, false
+		}
+		if c.action(next) != current {
+			return next, true
+		}
+		at = next
+	}
+}
+
+// importReader returns a reader of ScaleCalendar imports from ConfigMaps and
+// from files in the calendar import directory.
+func (r *ScalerReconciler) importReader(ctx context.Context) importReader {
+	return func(imp autoscalev1alpha1.CalendarImport) (string, error) {
+		if imp.Path != "" {
+			return readImportFile(r.ImportDir, imp.Path)
+		}
+		reader := r.APIReader
+		if reader == nil {
+			reader = r.Client
+		}
+		ref := imp.ConfigMapKeyRef
+		var cm corev1.ConfigMap
+		if err := reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &cm); err != nil {
+			return "", fmt.Errorf("failed to get configmap %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		data, ok := cm.Data[ref.Key]
+		if !ok {
+			return "", fmt.Errorf("configmap %s/%s has no key %s", ref.Namespace, ref.Name, ref.Key)
+		}
+		return data, nil
+	}
+}
+
+// readImportFile reads path within dir. Path is relative to dir, or absolute
+// under it; paths leaving dir, including through symbolic links, are rejected.
+func readImportFile(dir, path string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("path imports are disabled, set --calendar-import-dir")
+	}
+	rel := path
+	if filepath.IsAbs(path) {
+		var err error
+		if rel, err = filepath.Rel(dir, path); err != nil {
+			return "", fmt.Errorf("path %s is outside the calendar import directory", path)
+		}
+	}
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("path %s is outside the calendar import directory", path)
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open the calendar import directory: %w", err)
+	}
+	defer root.Close()
+	f, err := root.Open(rel)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return string(data), nil
+}
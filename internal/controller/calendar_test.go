@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+)
+
+func TestEasterSunday(t *testing.T) {
+	tests := []struct {
+		year     int
+		expected string
+	}{
+		{2024, "2024-03-31"},
+		{2025, "2025-04-20"},
+		{2026, "2026-04-05"},
+		{2038, "2038-04-25"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, easterSunday(test.year).Format(time.DateOnly), "unexpected easter for year: %d", test.year)
+	}
+}
+
+func TestIsHoliday(t *testing.T) {
+	tests := []struct {
+		country  string
+		day      string
+		expected bool
+	}{
+		{"FR", "2026-07-14", true},
+		{"fr", "2026-05-14", true}, // Ascension, lowercase country
+		{"FR", "2026-07-15", false},
+		{"DE", "2026-04-03", true}, // Good Friday
+		{"DE", "2026-10-03", true},
+		{"GB", "2022-12-26", true}, // Christmas on Sunday, Boxing Day on Monday
+		{"GB", "2022-12-27", true}, // substitute Christmas
+		{"GB", "2026-05-25", true}, // last Monday of May
+		{"US", "2025-11-27", true}, // Thanksgiving
+		{"US", "2021-12-31", true}, // New Year 2022 observed
+		{"US", "2026-07-03", true}, // July 4th on Saturday
+		{"NL", "2025-04-26", true}, // King's Day moved from Sunday
+		{"XX", "2026-01-01", false},
+	}
+
+	for _, test := range tests {
+		day, _ := time.Parse(time.DateOnly, test.day)
+		assert.Equal(t, test.expected, isHoliday(test.country, day), "unexpected result for %s on %s", test.country, test.day)
+	}
+}
+
+func TestCalendarAction(t *testing.T) {
+	cal, err := newCalendar(&autoscalev1alpha1.ScaleCalendarSpec{
+		TimeZone: "Europe/Berlin",
+		Holidays: []string{"DE"},
+		Exceptions: []autoscalev1alpha1.CalendarException{
+			{Date: "2026-12-24", EndDate: "2026-12-31", Action: autoscalev1alpha1.CalendarActionDown},
+			{Date: "2026-10-03", Window: "09:00-12:00", Action: autoscalev1alpha1.CalendarActionUp},
+		},
+		Imports: []autoscalev1alpha1.CalendarImport{
+			{Path: "/etc/kubescale/closures.ics"},
+			{ConfigMapKeyRef: &autoscalev1alpha1.ConfigMapKeyReference{Namespace: "hr", Name: "calendars", Key: "stocktake.ics"}, Action: autoscalev1alpha1.CalendarActionUp},
+		},
+	}, nil, func(imp autoscalev1alpha1.CalendarImport) (string, error) {
+		if imp.Path != "" {
+			return "BEGIN:VEVENT\nUID:summer\nDTSTART;VALUE=DATE:20260810\nDTEND;VALUE=DATE:20260815\nEND:VEVENT\n" +
+				"BEGIN:VEVENT\nUID:bridge\nDTSTART;VALUE=DATE:20261224\nEND:VEVENT\n", nil
+		}
+		return "BEGIN:VEVENT\nUID:stocktake\nDTSTART:20261003T060000Z\nDURATION:PT2H\nRRULE:FREQ=YEARLY\nEND:VEVENT\n", nil
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		time     string
+		expected autoscalev1alpha1.CalendarAction
+	}{
+		{"2026-12-23T22:59:00Z", ""},                                   // 23:59 in Berlin
+		{"2026-12-23T23:00:00Z", autoscalev1alpha1.CalendarActionDown}, // midnight in Berlin
+		{"2026-12-31T22:59:00Z", autoscalev1alpha1.CalendarActionDown},
+		{"2026-12-31T23:00:00Z", autoscalev1alpha1.CalendarActionDown}, // New Year holiday
+		{"2027-01-01T23:00:00Z", ""},
+		{"2026-10-03T08:00:00Z", autoscalev1alpha1.CalendarActionUp},   // exception beats holiday
+		{"2026-10-03T10:00:00Z", autoscalev1alpha1.CalendarActionDown}, // 12:00 in Berlin, back to holiday
+		{"2026-06-10T10:00:00Z", ""},
+		{"2026-08-09T22:00:00Z", autoscalev1alpha1.CalendarActionDown}, // imported closure
+		{"2026-08-14T21:59:00Z", autoscalev1alpha1.CalendarActionDown},
+		{"2026-08-14T22:00:00Z", ""},
+		{"2026-10-03T06:30:00Z", autoscalev1alpha1.CalendarActionUp}, // import beats holiday
+		{"2026-10-03T07:30:00Z", autoscalev1alpha1.CalendarActionUp}, // exception beats holiday
+	}
+
+	for _, test := range tests {
+		now, _ := time.Parse(time.RFC3339, test.time)
+		assert.Equal(t, test.expected, cal.action(now), "unexpected action at %s", test.time)
+	}
+}
+
+func TestNewCalendarErrors(t *testing.T) {
+	specs := []autoscalev1alpha1.ScaleCalendarSpec{
+		{TimeZone: "Mars/Olympus"},
+		{Holidays: []string{"XX"}},
+		{Exceptions: []autoscalev1alpha1.CalendarException{{Date: "2026-13-01"}}},
+		{Exceptions: []autoscalev1alpha1.CalendarException{{Date: "2026-12-24", EndDate: "2026-12-01"}}},
+		{Exceptions: []autoscalev1alpha1.CalendarException{{Date: "2026-12-24", Window: "18:00-08:00"}}},
+		{Imports: []autoscalev1alpha1.CalendarImport{{}}},
+		{Imports: []autoscalev1alpha1.CalendarImport{{Path: "/missing.ics"}}},
+		{Imports: []autoscalev1alpha1.CalendarImport{{Path: "/invalid.ics"}}},
+	}
+	read := func(imp autoscalev1alpha1.CalendarImport) (string, error) {
+		if imp.Path == "/missing.ics" {
+			return "", errors.New("not found")
+		}
+		return "BEGIN:VEVENT\nDTSTART:20260101\nRRULE:FREQ=SECONDLY\nEND:VEVENT", nil
+	}
+
+	for _, spec := range specs {
+		_, err := newCalendar(&spec, nil, read)
+		assert.Error(t, err, "expected an error for spec: %+v", spec)
+	}
+}
+
+func TestReadImportFile(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "calendars")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "teams"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "teams", "a.ics"), []byte("BEGIN:VCALENDAR"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "secret"), []byte("token"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(base, "secret"), filepath.Join(dir, "link.ics")))
+
+	tests := []struct {
+		name string
+		dir  string
+		path string
+		ok   bool
+	}{
+		{"relative", dir, "teams/a.ics", true},
+		{"absolute within the directory", dir, filepath.Join(dir, "teams", "a.ics"), true},
+		{"imports disabled", "", filepath.Join(dir, "teams", "a.ics"), false},
+		{"parent directory", dir, "../secret", false},
+		{"parent directory within the path", dir, "teams/../../secret", false},
+		{"absolute outside the directory", dir, filepath.Join(base, "secret"), false},
+		{"absolute system file", dir, "/etc/passwd", false},
+		{"symbolic link out of the directory", dir, "link.ics", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := readImportFile(tt.dir, tt.path)
+			if !tt.ok {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "BEGIN:VCALENDAR", data)
+		})
+	}
+}
@@ -24,8 +24,8 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
-	corev1 "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -33,12 +33,28 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+	"github.com/cicd-toolkit/kubescale/internal/duration"
 )
 
 // ScalerReconciler reconciles a Scaler object
 type ScalerReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Presets resolves "@name" schedule references. It may be nil.
+	Presets *PresetRegistry
+	// Location is the timezone of windows without one, in namespaces
+	// without kubescale/timezone. It defaults to UTC.
+	Location *time.Location
+	// APIReader reads the ConfigMaps ScaleCalendars import from, which are
+	// not cached. It defaults to the client.
+	APIReader client.Reader
+	// ImportDir is the directory ScaleCalendars import files from. Path
+	// imports are rejected when it is empty.
+	ImportDir string
 }
 
 const (
@@ -46,71 +62,331 @@ const (
 	UptimeAnnotation           = BaseAnnotation + "/uptime"
 	DowntimeAnnotation         = BaseAnnotation + "/downtime"
 	PreviousReplicasAnnotation = BaseAnnotation + "/previous-replicas"
+	SuspendedAnnotation        = BaseAnnotation + "/suspended"
 	CustomReplicaAnnotation    = BaseAnnotation + "/replicas"
+	ProfilesAnnotation         = BaseAnnotation + "/profiles"
+	ReplicaRoundingAnnotation  = BaseAnnotation + "/replicas-rounding"
+	MinReplicasAnnotation      = BaseAnnotation + "/min-replicas"
 	ExcludeAnnotation          = BaseAnnotation + "/exclude"
 	ExcludeUntilAnnotation     = BaseAnnotation + "/exclude-until"
 	UpDurationAnnotation       = BaseAnnotation + "/up"
 	DownDurationAnnotation     = BaseAnnotation + "/down"
+	UpUntilAnnotation          = BaseAnnotation + "/up-until"
+	DownUntilAnnotation        = BaseAnnotation + "/down-until"
+	CalendarAnnotation         = BaseAnnotation + "/calendar"
+	TimezoneAnnotation         = BaseAnnotation + "/timezone"
 )
 
+// statusResyncPeriod bounds how stale the status of a Scaler, ClusterScaler
+// or ScaleCalendar can get.
+const statusResyncPeriod = 5 * time.Minute
+
 // +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scalers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scalers/finalizers,verbs=update
-
-// Reconcile is part of the main Kubernetes reconciliation loop
+// +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=clusterscalers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=clusterscalers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scalecalendars,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scalecalendars/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scaleoverrides,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scaleoverrides/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scalepolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scalegroups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scalegroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=autoscale.kubescale.io,resources=scalepolicies/status,verbs=get;update;patch
+
+// Reconcile applies a Scaler to the workloads it currently selects, so that a
+// new or edited schedule takes effect without waiting for the next poll, and
+// reports what it did in the Scaler status.
 func (r *ScalerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
-	logger.Info("Reconcile start")
-	return ctrl.Result{}, nil
+
+	var scaler autoscalev1alpha1.Scaler
+	if err := r.Get(ctx, req.NamespacedName, &scaler); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	now := time.Now().UTC()
+	spec := &scaler.Spec
+	if err := validateSchedule(r.Presets, &spec.ScheduleSpec, spec.Target.Selector); err != nil {
+		logger.Error(err, "Invalid Scaler schedule", "namespace", scaler.Namespace, "name", scaler.Name)
+		setScalerStatus(&scaler, nil, nil, err, nil, now)
+		return ctrl.Result{}, r.Status().Update(ctx, &scaler)
+	}
+
+	sources, err := r.loadSources(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	targets := r.applyTargets(ctx, sources, scaler.Namespace, func(obj client.Object) bool {
+		return scalerSelects(&scaler, obj)
+	})
+
+	schedule := &scheduleTarget{
+		sources:  sources,
+		defaults: scheduleAnnotations(&spec.ScheduleSpec),
+		location: sources.locationFor(scaler.Namespace),
+	}
+	next := schedule.nextTransition(schedule.defaults, now)
+	setScalerStatus(&scaler, targets, next, nil, schedule.location, now)
+	if err := r.Status().Update(ctx, &scaler); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Refresh at the next transition, and regularly so that new or changed
+	// workloads show up in the status
+	requeue := statusResyncPeriod
+	if next != nil && next.Sub(now) < requeue {
+		requeue = next.Sub(now)
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// reconcileClusterScaler applies a ClusterScaler to the workloads it
+// currently selects across namespaces.
+func (r *ScalerReconciler) reconcileClusterScaler(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var cs autoscalev1alpha1.ClusterScaler
+	if err := r.Get(ctx, req.NamespacedName, &cs); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	spec := &cs.Spec
+	if err := validateSchedule(r.Presets, &spec.ScheduleSpec, &spec.NamespaceSelector, spec.Target.Selector); err != nil {
+		logger.Error(err, "Invalid ClusterScaler schedule", "name", cs.Name)
+		setClusterScalerStatus(&cs, 0, nil, err)
+		return ctrl.Result{}, r.Status().Update(ctx, &cs)
+	}
+
+	sources, err := r.loadSources(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	targets := r.applyTargets(ctx, sources, "", func(obj client.Object) bool {
+		return sources.clusterScalerSelects(&cs, obj)
+	})
+	setClusterScalerStatus(&cs, sources.clusterScalerNamespaces(&cs), targets, nil)
+	if err := r.Status().Update(ctx, &cs); err != nil {
+		return ctrl.Result{}, err
+	}
+	// Refresh regularly so that new namespaces and workloads show up in the
+	// status
+	return ctrl.Result{RequeueAfter: statusResyncPeriod}, nil
+}
+
+// reconcileScaleCalendar reports whether a ScaleCalendar can be read and the
+// state it forces in its status.
+func (r *ScalerReconciler) reconcileScaleCalendar(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var item autoscalev1alpha1.ScaleCalendar
+	if err := r.Get(ctx, req.NamespacedName, &item); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	now := time.Now().UTC()
+	cal, err := newCalendar(&item.Spec, r.Location, r.importReader(ctx))
+	if err != nil {
+		logger.Error(err, "Invalid ScaleCalendar", "name", item.Name)
+	}
+	setScaleCalendarStatus(&item, cal, err, now)
+	if err := r.Status().Update(ctx, &item); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Refresh at the next transition, and regularly so that edited imports
+	// show up in the status
+	requeue := statusResyncPeriod
+	if next := item.Status.NextTransitionTime; next != nil && next.Sub(now) < requeue {
+		requeue = next.Sub(now)
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// reconcileScaleOverride applies a ScaleOverride as soon as it is created and
+// deletes it once it expires.
+func (r *ScalerReconciler) reconcileScaleOverride(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var override autoscalev1alpha1.ScaleOverride
+	if err := r.Get(ctx, req.NamespacedName, &override); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	now := time.Now()
+	if overrideExpired(&override, now) {
+		setScaleOverrideStatus(&override, autoscalev1alpha1.OverridePhaseExpired, nil, nil)
+		if err := r.Status().Update(ctx, &override); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		r.deleteExpiredOverride(ctx, &override)
+		return ctrl.Result{}, nil
+	}
+
+	if err := validateOverride(&override.Spec); err != nil {
+		logger.Error(err, "Invalid ScaleOverride", "namespace", override.Namespace, "name", override.Name)
+		setScaleOverrideStatus(&override, autoscalev1alpha1.OverridePhaseInvalid, nil, err)
+		return ctrl.Result{}, r.Status().Update(ctx, &override)
+	}
+
+	sources, err := r.loadSources(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	targets := r.applyTargets(ctx, sources, override.Namespace, func(obj client.Object) bool {
+		return overrideSelects(&override, obj)
+	})
+	setScaleOverrideStatus(&override, autoscalev1alpha1.OverridePhaseActive, targets, nil)
+	if err := r.Status().Update(ctx, &override); err != nil {
+		return ctrl.Result{}, err
+	}
+	// Come back at expiry to delete the override and restore the schedule
+	return ctrl.Result{RequeueAfter: override.Spec.ExpiresAt.Sub(now)}, nil
+}
+
+// applyTargets scales the workloads of namespace, or of all namespaces when
+// empty, that selects reports as managed, and returns the resulting status of
+// each of them.
+func (r *ScalerReconciler) applyTargets(
+	ctx context.Context,
+	sources *scheduleSources,
+	namespace string,
+	selects func(client.Object) bool,
+) []autoscalev1alpha1.TargetStatus {
+	logger := log.FromContext(ctx)
+
+	var statuses []autoscalev1alpha1.TargetStatus
+	now := time.Now().UTC()
+	objs := r.listTargets(ctx, namespace)
+	held := sources.heldMembers(objs, now)
+	for _, obj := range objs {
+		if !selects(obj) {
+			continue
+		}
+		target := sources.targetFor(obj, now)
+		r.transformAnnotations(ctx, obj, target.location, now)
+		waiting, isHeld := held[obj]
+		var err error
+		if isHeld {
+			logger.Info("Holding back group member", "namespace", obj.GetNamespace(), "name", obj.GetName(), "reason", waiting)
+		} else if err = r.handleObject(ctx, target, obj, now); err != nil {
+			logger.Error(err, "Failed to scale target", "namespace", obj.GetNamespace(), "name", obj.GetName())
+		}
+		status := targetStatus(target, obj, err, waiting, now)
+		if err == nil && status.Phase == autoscalev1alpha1.TargetPhaseError {
+			logger.Error(nil, "Invalid schedule", "namespace", obj.GetNamespace(), "name", obj.GetName(), "error", status.Message)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ScalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	go func() {
+		logger := mgr.GetLogger().WithName("poller")
 		time.Sleep(10 * time.Second) // Wait for the controller to be fully initialized
 		for {
-			if err := r.checkResources(); err != nil {
-				fmt.Printf("Error checking resources: %v\n", err)
+			wait := 1 * time.Minute
+			next, err := r.checkResources()
+			if err != nil {
+				logger.Error(err, "Error checking resources")
+			}
+			// Wake up exactly at the next transition when it comes sooner
+			if until := time.Until(next); !next.IsZero() && until < wait {
+				wait = max(until, 0)
 			}
-			time.Sleep(1 * time.Minute)
+			time.Sleep(wait)
 		}
 	}()
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&autoscalev1alpha1.ClusterScaler{}). // Watches clusterscalers
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(reconcile.Func(r.reconcileClusterScaler))
+	if err != nil {
+		return err
+	}
+	err = ctrl.NewControllerManagedBy(mgr).
+		For(&autoscalev1alpha1.ScaleCalendar{}). // Watches scalecalendars
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(reconcile.Func(r.reconcileScaleCalendar))
+	if err != nil {
+		return err
+	}
+	err = ctrl.NewControllerManagedBy(mgr).
+		For(&autoscalev1alpha1.ScaleOverride{}). // Watches scaleoverrides
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(reconcile.Func(r.reconcileScaleOverride))
+	if err != nil {
+		return err
+	}
+	err = ctrl.NewControllerManagedBy(mgr).
+		For(&autoscalev1alpha1.ScaleGroup{}). // Watches scalegroups
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(reconcile.Func(r.reconcileScaleGroup))
+	if err != nil {
+		return err
+	}
+	err = ctrl.NewControllerManagedBy(mgr).
+		For(&autoscalev1alpha1.ScalePolicy{}). // Watches scalepolicies
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(reconcile.Func(r.reconcileScalePolicy))
+	if err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&appsv1.Deployment{}).   // Watches deployments
-		Owns(&appsv1.StatefulSet{}). // Watches statefulsets
-		Owns(&batchv1.CronJob{}).    // Watches cronjobs
-		Owns(&appsv1.DaemonSet{}).   // Watches daemonsets
+		For(&autoscalev1alpha1.Scaler{}). // Watches scalers
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
 		Complete(r)
 }
 
-func (r *ScalerReconciler) checkResources() error {
+// checkResources applies the schedules to every workload and returns the
+// earliest time at which one of them next changes, zero when none does
+// within transitionHorizon.
+func (r *ScalerReconciler) checkResources() (time.Time, error) {
 	ctx := context.Background()
 	log := ctrllog.FromContext(ctx)
 	now := time.Now().UTC()
 
-	// Fetch namespace annotations
-	nsMapAnnotations := make(map[string]map[string]string)
-	var nsList corev1.NamespaceList
-	if err := r.Client.List(ctx, &nsList); err == nil { // List all namespaces
-		for _, ns := range nsList.Items {
-			if nsAnn := ns.GetAnnotations(); nsAnn != nil {
-				nsMapAnnotations[ns.Name] = nsAnn
-			}
+	sources, err := r.loadSources(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load schedule sources: %w", err)
+	}
+
+	objs := r.listTargets(ctx, "") // Fetch all namespaces
+	held := sources.heldMembers(objs, now)
+	var next time.Time
+	for _, obj := range objs {
+		if _, ok := held[obj]; ok {
+			continue
+		}
+		target := sources.targetFor(obj, now)
+		r.transformAnnotations(ctx, obj, target.location, now)
+		if err := r.handleObject(ctx, target, obj, now); err != nil {
+			log.Error(err, "Error handling resource", "namespace", obj.GetNamespace(), "name", obj.GetName())
+		}
+		annotations := MergeAnnotations(target.defaults, obj.GetAnnotations())
+		if at := target.nextTransition(annotations, now); at != nil && (next.IsZero() || at.Time.Before(next)) {
+			next = at.Time
 		}
-	} else {
-		log.Error(err, "Error listing namespaces")
 	}
 
+	return next, nil
+}
+
+// listTargets returns every workload kubescale can scale in namespace, or in
+// all namespaces when namespace is empty. Listing errors are logged per kind
+// so one missing API does not block the others.
+func (r *ScalerReconciler) listTargets(ctx context.Context, namespace string) []client.Object {
+	log := ctrllog.FromContext(ctx)
+	var targets []client.Object
+
 	// --- Deployments ---
 	var deployList appsv1.DeploymentList
-	if err := r.Client.List(ctx, &deployList, client.InNamespace("")); err == nil { // Fetch all namespaces
-		for _, dep := range deployList.Items {
-			r.transformAnnotations(ctx, &dep, now)
-			nsAnnotations := nsMapAnnotations[dep.GetNamespace()]
-			r.handleReplicatedResource(ctx, &dep.ObjectMeta, nsAnnotations, dep.Spec.Replicas, func(newReplicas int32) error {
-				dep.Spec.Replicas = &newReplicas
-				return r.Client.Update(ctx, &dep)
-			})
+	if err := r.Client.List(ctx, &deployList, client.InNamespace(namespace)); err == nil {
+		for i := range deployList.Items {
+			targets = append(targets, &deployList.Items[i])
 		}
 	} else {
 		log.Error(err, "Error listing deployments")
@@ -118,14 +394,9 @@ func (r *ScalerReconciler) checkResources() error {
 
 	// --- StatefulSets ---
 	var stsList appsv1.StatefulSetList
-	if err := r.Client.List(ctx, &stsList, client.InNamespace("")); err == nil { // Fetch all namespaces
-		for _, sts := range stsList.Items {
-			r.transformAnnotations(ctx, &sts, now)
-			nsAnnotations := nsMapAnnotations[sts.GetNamespace()]
-			r.handleReplicatedResource(ctx, &sts.ObjectMeta, nsAnnotations, sts.Spec.Replicas, func(newReplicas int32) error {
-				sts.Spec.Replicas = &newReplicas
-				return r.Client.Update(ctx, &sts)
-			})
+	if err := r.Client.List(ctx, &stsList, client.InNamespace(namespace)); err == nil {
+		for i := range stsList.Items {
+			targets = append(targets, &stsList.Items[i])
 		}
 	} else {
 		log.Error(err, "Error listing statefulsets")
@@ -133,14 +404,9 @@ func (r *ScalerReconciler) checkResources() error {
 
 	// --- DaemonSets ---
 	var dsList appsv1.DaemonSetList
-	if err := r.Client.List(ctx, &dsList, client.InNamespace("")); err == nil { // Fetch all namespaces
-		for _, ds := range dsList.Items {
-			r.transformAnnotations(ctx, &ds, now)
-			nsAnnotations := nsMapAnnotations[ds.GetNamespace()]
-			r.handleDaemonSets(ctx, nsAnnotations, &ds, func(newReplicas int32) error {
-				// DaemonSets do not have replicas, so we don't need to update them
-				return nil
-			})
+	if err := r.Client.List(ctx, &dsList, client.InNamespace(namespace)); err == nil {
+		for i := range dsList.Items {
+			targets = append(targets, &dsList.Items[i])
 		}
 	} else {
 		log.Error(err, "Error listing daemonsets")
@@ -148,97 +414,170 @@ func (r *ScalerReconciler) checkResources() error {
 
 	// --- CronJobs ---
 	var cjList batchv1.CronJobList
-	if err := r.Client.List(ctx, &cjList, client.InNamespace("")); err == nil { // Fetch all namespaces
-		for _, cj := range cjList.Items {
-			r.transformAnnotations(ctx, &cj, now)
-			nsAnnotations := nsMapAnnotations[cj.GetNamespace()]
-			r.handleCronJob(ctx, nsAnnotations, &cj)
+	if err := r.Client.List(ctx, &cjList, client.InNamespace(namespace)); err == nil {
+		for i := range cjList.Items {
+			targets = append(targets, &cjList.Items[i])
 		}
 	} else {
 		log.Error(err, "Error listing cronjobs")
 	}
 
+	// --- Prometheus ---
 	dynamicClient, err := dynamic.NewForConfig(config.GetConfigOrDie())
 	if err != nil {
-		return fmt.Errorf("failed to create dynamic client: %w", err)
+		log.Error(err, "Failed to create dynamic client")
+		return targets
 	}
-
-	// --- Prometheus ---
-
-	// List all CRDs
-	crdList, err := dynamicClient.Resource(PrometheusGVR).List(ctx, meta.ListOptions{})
+	promList, err := dynamicClient.Resource(PrometheusGVR).Namespace(namespace).List(ctx, meta.ListOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to list Prometheus: %w", err)
+		log.Error(err, "Error listing prometheus")
+		return targets
+	}
+	for i := range promList.Items {
+		targets = append(targets, &promList.Items[i])
 	}
 
-	// Process CRDs
-	for _, crd := range crdList.Items {
-		r.transformAnnotations(ctx, &crd, now)
-		nsAnnotations := nsMapAnnotations[crd.GetNamespace()]
-		err = r.handlePrometheus(ctx, nsAnnotations, &crd)
-		if err != nil {
+	return targets
+}
+
+// handleObject dispatches obj to the handler for its kind, along with what
+// the schedule sources resolved for it, to be evaluated at now.
+func (r *ScalerReconciler) handleObject(ctx context.Context, target *scheduleTarget, obj client.Object, now time.Time) error {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return r.handleReplicatedResource(ctx, target, &o.ObjectMeta, o.Spec.Replicas, now, func(newReplicas int32) error {
+			o.Spec.Replicas = &newReplicas
+			return r.Client.Update(ctx, o)
+		})
+	case *appsv1.StatefulSet:
+		return r.handleReplicatedResource(ctx, target, &o.ObjectMeta, o.Spec.Replicas, now, func(newReplicas int32) error {
+			o.Spec.Replicas = &newReplicas
+			return r.Client.Update(ctx, o)
+		})
+	case *appsv1.DaemonSet:
+		return r.handleDaemonSets(ctx, target, o, now, func(newReplicas int32) error {
+			// DaemonSets do not have replicas, so we don't need to update them
+			return nil
+		})
+	case *batchv1.CronJob:
+		return r.handleCronJob(ctx, target, o, now)
+	case *unstructured.Unstructured:
+		if err := r.handlePrometheus(ctx, target, o, now); err != nil {
 			return fmt.Errorf("failed to handlePrometheus: %w", err)
 		}
 	}
-
 	return nil
 }
 
-func (r *ScalerReconciler) transformAnnotations(ctx context.Context, obj client.Object, now time.Time) {
+// transformAnnotations resolves kubescale/up and kubescale/down on obj, drops
+// its expired kubescale/up-until and writes the result back. Expiries are
+// written in loc unless the request names a timezone. When the mutating
+// webhook is enabled this already happened at admission, and there is nothing
+// left to do here.
+func (r *ScalerReconciler) transformAnnotations(ctx context.Context, obj client.Object, loc *time.Location, now time.Time) {
 	log := ctrllog.FromContext(ctx)
 	ann := obj.GetAnnotations()
 	if ann == nil {
 		return
 	}
 
-	tz := "UTC"
-	start := now
-	foundAnnotations := false
-
-	// Handle 'kubescale/up'
-	if val, ok := ann[UpDurationAnnotation]; ok && val != "" {
+	resolved, changed, err := ResolveDurationAnnotations(ann, now, loc)
+	if err != nil {
+		log.Error(err, "Invalid duration format", "namespace", obj.GetNamespace(), "name", obj.GetName())
+		return
+	}
 
-		foundAnnotations = true
-		duration, err := parseHumanDuration(val)
+	if changed {
+		obj.SetAnnotations(resolved)
+		// Patch the resource with updated annotations
+		err := r.Client.Update(ctx, obj)
 		if err != nil {
-			log.Error(err, "Invalid duration format ", UpDurationAnnotation, obj.GetNamespace(), "/", obj.GetName())
-			return
+			log.Error(err, "Failed to patch annotations", "namespace", obj.GetNamespace(), "name", obj.GetName())
+		} else {
+			log.Info("Transformed annotations", "namespace", obj.GetNamespace(), "name", obj.GetName())
 		}
+	}
+}
 
-		end := now.Add(duration)
-		day := end.Weekday().String()[:3]
-		ann[UptimeAnnotation] = fmt.Sprintf("%s-%s %s-%s %s", day, day, start.Format("15:04"), end.Format("15:04"), tz)
-		delete(ann, UpDurationAnnotation)
+// ResolveDurationAnnotations rewrites kubescale/up and kubescale/down into
+// kubescale/up-until and kubescale/down-until, expiring the duration after
+// now, and drops a kubescale/up-until that has passed. A passed
+// kubescale/down-until is left to the handlers, which restore the workload
+// and drop it. Expiries are written in loc, UTC when nil, unless the request
+// names a timezone. A request replaces an expiry in the opposite direction.
+// It returns a new map and whether anything changed; ann itself is left
+// untouched.
+func ResolveDurationAnnotations(ann map[string]string, now time.Time, loc *time.Location) (map[string]string, bool, error) {
+	resolved := make(map[string]string, len(ann))
+	for k, v := range ann {
+		resolved[k] = v
 	}
+	changed := false
 
-	// Handle 'kubescale/down'
-	if val, ok := ann[DownDurationAnnotation]; ok && val != "" {
-		foundAnnotations = true
-		duration, err := parseHumanDuration(val)
+	if until, err := time.Parse(time.RFC3339, ann[UpUntilAnnotation]); err == nil && !now.Before(until) {
+		delete(resolved, UpUntilAnnotation)
+		changed = true
+	}
+
+	for _, request := range []struct{ durationKey, untilKey, opposite, oppositeUntil string }{
+		{UpDurationAnnotation, UpUntilAnnotation, DownDurationAnnotation, DownUntilAnnotation},
+		{DownDurationAnnotation, DownUntilAnnotation, UpDurationAnnotation, UpUntilAnnotation},
+	} {
+		val, ok := ann[request.durationKey]
+		if !ok || val == "" {
+			continue
+		}
+		until, err := parseDurationRequest(val, now, loc)
 		if err != nil {
-			log.Error(err, "Invalid duration format ", DownDurationAnnotation, obj.GetNamespace(), "/", obj.GetName())
-			return
+			return ann, false, fmt.Errorf("%s: %w", request.durationKey, err)
+		}
+		resolved[request.untilKey] = until
+		delete(resolved, request.durationKey)
+		if _, both := ann[request.opposite]; !both {
+			delete(resolved, request.oppositeUntil)
 		}
-		start := now
-		end := now.Add(duration)
-		day := end.Weekday().String()[:3]
-		ann[DowntimeAnnotation] = fmt.Sprintf("%s-%s %s-%s %s", day, day, start.Format("15:04"), end.Format("15:04"), tz)
-		delete(ann, DownDurationAnnotation)
+		changed = true
 	}
 
-	if foundAnnotations {
-		obj.SetAnnotations(ann)
-		// Patch the resource with updated annotations
-		err := r.Client.Update(ctx, obj)
-		if err != nil {
-			log.Error(err, "Failed to patch annotations on ", DownDurationAnnotation, obj.GetNamespace(), "/", obj.GetName())
-		} else {
-			log.Info("Transformed annotations for ", obj.GetNamespace(), "/", obj.GetName())
+	return resolved, changed, nil
+}
+
+// parseDurationRequest parses "<duration> [Timezone]" and returns the RFC3339
+// time the duration expires after now. The timezone, loc when absent, only
+// sets the offset the expiry is written with.
+func parseDurationRequest(value string, now time.Time, loc *time.Location) (string, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", fmt.Errorf("invalid value %q, expected \"<duration> [Timezone]\"", value)
+	}
+	d, err := duration.Parse(fields[0])
+	if err != nil {
+		return "", err
+	}
+	if d == 0 {
+		return "", fmt.Errorf("invalid duration %q, expected a positive duration", fields[0])
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	if len(fields) == 2 {
+		if loc, err = time.LoadLocation(fields[1]); err != nil {
+			return "", fmt.Errorf("unknown timezone %q, expected an IANA name such as \"Europe/Paris\"", fields[1])
 		}
 	}
+	return now.Add(d).In(loc).Format(time.RFC3339), nil
 }
 
-func shouldSkipResource(meta *meta.ObjectMeta) bool {
+// shouldSkipResource reports whether the controller must leave a workload
+// untouched at now. An active ScaleOverride wins over the exclude annotations.
+func shouldSkipResource(meta *meta.ObjectMeta, override autoscalev1alpha1.OverrideState, now time.Time) bool {
+	switch override {
+	case autoscalev1alpha1.OverrideExcluded:
+		return true
+	case autoscalev1alpha1.OverrideUp, autoscalev1alpha1.OverrideDown:
+		return false
+	}
+
 	ann := meta.Annotations
 	if ann == nil {
 		return false
@@ -252,7 +591,7 @@ func shouldSkipResource(meta *meta.ObjectMeta) bool {
 	// Time-based exclude
 	if until, ok := ann[ExcludeUntilAnnotation]; ok {
 		t, err := time.Parse(time.RFC3339, until)
-		if err == nil && time.Now().Before(t) {
+		if err == nil && now.Before(t) {
 			return true
 		}
 	}
@@ -23,6 +23,8 @@ import (
 	"github.com/onsi/gomega"
 	. "github.com/onsi/gomega"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
 )
 
 var _ = Describe("Scaler Controller", func() {
@@ -36,11 +38,13 @@ var _ = Describe("Scaler Controller", func() {
 	})
 })
 var _ = Describe("shouldSkipResource", func() {
+	now := time.Date(2026, 5, 13, 12, 0, 0, 0, time.UTC)
+
 	Context("when the resource has no annotations", func() {
 		It("should not skip the resource", func() {
 			objMeta := &meta.ObjectMeta{}
-			gomega.Expect(shouldSkipResource(objMeta)).To(gomega.BeFalse())
-			Expect(shouldSkipResource(objMeta)).To(BeFalse())
+			gomega.Expect(shouldSkipResource(objMeta, "", now)).To(gomega.BeFalse())
+			Expect(shouldSkipResource(objMeta, "", now)).To(BeFalse())
 		})
 	})
 
@@ -51,7 +55,7 @@ var _ = Describe("shouldSkipResource", func() {
 					"kubescale/exclude": "true",
 				},
 			}
-			Expect(shouldSkipResource(meta)).To(BeTrue())
+			Expect(shouldSkipResource(meta, "", now)).To(BeTrue())
 		})
 	})
 
@@ -62,31 +66,31 @@ var _ = Describe("shouldSkipResource", func() {
 					"kubescale/exclude": "false",
 				},
 			}
-			Expect(shouldSkipResource(meta)).To(BeFalse())
+			Expect(shouldSkipResource(meta, "", now)).To(BeFalse())
 		})
 	})
 
 	Context("when the resource has the 'kubescale/exclude-until' annotation with a future timestamp", func() {
 		It("should skip the resource", func() {
-			futureTime := time.Now().Add(1 * time.Hour).Format(time.RFC3339)
+			futureTime := now.Add(1 * time.Hour).Format(time.RFC3339)
 			meta := &meta.ObjectMeta{
 				Annotations: map[string]string{
 					"kubescale/exclude-until": futureTime,
 				},
 			}
-			Expect(shouldSkipResource(meta)).To(BeTrue())
+			Expect(shouldSkipResource(meta, "", now)).To(BeTrue())
 		})
 	})
 
 	Context("when the resource has the 'kubescale/exclude-until' annotation with a past timestamp", func() {
 		It("should not skip the resource", func() {
-			pastTime := time.Now().Add(-1 * time.Hour).Format(time.RFC3339)
+			pastTime := now.Add(-1 * time.Hour).Format(time.RFC3339)
 			meta := &meta.ObjectMeta{
 				Annotations: map[string]string{
 					"kubescale/exclude-until": pastTime,
 				},
 			}
-			Expect(shouldSkipResource(meta)).To(BeFalse())
+			Expect(shouldSkipResource(meta, "", now)).To(BeFalse())
 		})
 	})
 
@@ -97,7 +101,25 @@ var _ = Describe("shouldSkipResource", func() {
 					"kubescale/exclude-until": "invalid-timestamp",
 				},
 			}
-			Expect(shouldSkipResource(meta)).To(BeFalse())
+			Expect(shouldSkipResource(meta, "", now)).To(BeFalse())
+		})
+	})
+
+	Context("when an active ScaleOverride excludes the resource", func() {
+		It("should skip the resource", func() {
+			meta := &meta.ObjectMeta{}
+			Expect(shouldSkipResource(meta, autoscalev1alpha1.OverrideExcluded, now)).To(BeTrue())
+		})
+	})
+
+	Context("when an active ScaleOverride forces a state on an excluded resource", func() {
+		It("should not skip the resource", func() {
+			meta := &meta.ObjectMeta{
+				Annotations: map[string]string{
+					"kubescale/exclude": "true",
+				},
+			}
+			Expect(shouldSkipResource(meta, autoscalev1alpha1.OverrideUp, now)).To(BeFalse())
 		})
 	})
 })
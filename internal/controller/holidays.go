@@ -0,0 +1,212 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"time"
+)
+
+// holidayFunc returns the national public holidays of a country for a year,
+// as dates at midnight UTC. Regional holidays are left to calendar exceptions.
+type holidayFunc func(year int) []time.Time
+
+var holidayCalendars = map[string]holidayFunc{
+	"AT": func(y int) []time.Time {
+		e := easterSunday(y)
+		return []time.Time{
+			date(y, time.January, 1), date(y, time.January, 6), e.AddDate(0, 0, 1),
+			date(y, time.May, 1), e.AddDate(0, 0, 39), e.AddDate(0, 0, 50), e.AddDate(0, 0, 60),
+			date(y, time.August, 15), date(y, time.October, 26), date(y, time.November, 1),
+			date(y, time.December, 8), date(y, time.December, 25), date(y, time.December, 26),
+		}
+	},
+	"BE": func(y int) []time.Time {
+		e := easterSunday(y)
+		return []time.Time{
+			date(y, time.January, 1), e.AddDate(0, 0, 1), date(y, time.May, 1),
+			e.AddDate(0, 0, 39), e.AddDate(0, 0, 50), date(y, time.July, 21),
+			date(y, time.August, 15), date(y, time.November, 1), date(y, time.November, 11),
+			date(y, time.December, 25),
+		}
+	},
+	"DE": func(y int) []time.Time {
+		e := easterSunday(y)
+		return []time.Time{
+			date(y, time.January, 1), e.AddDate(0, 0, -2), e.AddDate(0, 0, 1),
+			date(y, time.May, 1), e.AddDate(0, 0, 39), e.AddDate(0, 0, 50),
+			date(y, time.October, 3), date(y, time.December, 25), date(y, time.December, 26),
+		}
+	},
+	"ES": func(y int) []time.Time {
+		e := easterSunday(y)
+		return []time.Time{
+			date(y, time.January, 1), date(y, time.January, 6), e.AddDate(0, 0, -2),
+			date(y, time.May, 1), date(y, time.August, 15), date(y, time.October, 12),
+			date(y, time.November, 1), date(y, time.December, 6), date(y, time.December, 8),
+			date(y, time.December, 25),
+		}
+	},
+	"FR": func(y int) []time.Time {
+		e := easterSunday(y)
+		return []time.Time{
+			date(y, time.January, 1), e.AddDate(0, 0, 1), date(y, time.May, 1),
+			date(y, time.May, 8), e.AddDate(0, 0, 39), e.AddDate(0, 0, 50),
+			date(y, time.July, 14), date(y, time.August, 15), date(y, time.November, 1),
+			date(y, time.November, 11), date(y, time.December, 25),
+		}
+	},
+	// England and Wales bank holidays, with weekend substitute days.
+	"GB": func(y int) []time.Time {
+		e := easterSunday(y)
+		days := []time.Time{
+			nextWeekday(date(y, time.January, 1)), e.AddDate(0, 0, -2), e.AddDate(0, 0, 1),
+			nthWeekday(y, time.May, time.Monday, 1), nthWeekday(y, time.May, time.Monday, -1),
+			nthWeekday(y, time.August, time.Monday, -1),
+		}
+		switch date(y, time.December, 25).Weekday() {
+		case time.Friday:
+			days = append(days, date(y, time.December, 25), date(y, time.December, 28))
+		case time.Saturday:
+			days = append(days, date(y, time.December, 27), date(y, time.December, 28))
+		case time.Sunday:
+			days = append(days, date(y, time.December, 26), date(y, time.December, 27))
+		default:
+			days = append(days, date(y, time.December, 25), date(y, time.December, 26))
+		}
+		return days
+	},
+	"IT": func(y int) []time.Time {
+		e := easterSunday(y)
+		return []time.Time{
+			date(y, time.January, 1), date(y, time.January, 6), e.AddDate(0, 0, 1),
+			date(y, time.April, 25), date(y, time.May, 1), date(y, time.June, 2),
+			date(y, time.August, 15), date(y, time.November, 1), date(y, time.December, 8),
+			date(y, time.December, 25), date(y, time.December, 26),
+		}
+	},
+	"NL": func(y int) []time.Time {
+		e := easterSunday(y)
+		kingsDay := date(y, time.April, 27)
+		if kingsDay.Weekday() == time.Sunday {
+			kingsDay = kingsDay.AddDate(0, 0, -1)
+		}
+		return []time.Time{
+			date(y, time.January, 1), e.AddDate(0, 0, 1), kingsDay,
+			e.AddDate(0, 0, 39), e.AddDate(0, 0, 50),
+			date(y, time.December, 25), date(y, time.December, 26),
+		}
+	},
+	// US federal holidays, observed on the Friday or Monday when they fall on
+	// a weekend.
+	"US": func(y int) []time.Time {
+		days := []time.Time{
+			nthWeekday(y, time.January, time.Monday, 3), nthWeekday(y, time.February, time.Monday, 3),
+			nthWeekday(y, time.May, time.Monday, -1), nthWeekday(y, time.September, time.Monday, 1),
+			nthWeekday(y, time.October, time.Monday, 2), nthWeekday(y, time.November, time.Thursday, 4),
+		}
+		fixed := []time.Time{
+			date(y, time.January, 1), date(y, time.July, 4),
+			date(y, time.November, 11), date(y, time.December, 25),
+		}
+		if y >= 2021 {
+			fixed = append(fixed, date(y, time.June, 19))
+		}
+		for _, d := range fixed {
+			days = append(days, observedUS(d))
+		}
+		// New Year's Day falling on a Saturday is observed on December 31
+		if nextNewYear := date(y+1, time.January, 1); nextNewYear.Weekday() == time.Saturday {
+			days = append(days, observedUS(nextNewYear))
+		}
+		return days
+	},
+}
+
+// isHoliday reports whether day is a public holiday in country. Unknown
+// countries have no holidays.
+func isHoliday(country string, day time.Time) bool {
+	holidays, ok := holidayCalendars[strings.ToUpper(country)]
+	if !ok {
+		return false
+	}
+	d := date(day.Year(), day.Month(), day.Day())
+	for _, h := range holidays(day.Year()) {
+		if h.Equal(d) {
+			return true
+		}
+	}
+	return false
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// easterSunday returns the date of Western Easter using the anonymous
+// Gregorian algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// nthWeekday returns the nth weekday of a month, counting from the end of
+// the month when n is negative.
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n > 0 {
+		first := date(year, month, 1)
+		offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, offset+(n-1)*7)
+	}
+	last := date(year, month+1, 0)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset+(n+1)*7)
+}
+
+// nextWeekday moves a date falling on a weekend to the following Monday.
+func nextWeekday(d time.Time) time.Time {
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDate(0, 0, 2)
+	case time.Sunday:
+		return d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+func observedUS(d time.Time) time.Time {
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDate(0, 0, -1)
+	case time.Sunday:
+		return d.AddDate(0, 0, 1)
+	}
+	return d
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+)
+
+// overrideExpired reports whether override no longer applies at now.
+func overrideExpired(override *autoscalev1alpha1.ScaleOverride, now time.Time) bool {
+	return !now.Before(override.Spec.ExpiresAt.Time)
+}
+
+// overrideSelects reports whether override targets obj.
+func overrideSelects(override *autoscalev1alpha1.ScaleOverride, obj client.Object) bool {
+	if override.Namespace != obj.GetNamespace() {
+		return false
+	}
+	if ref := override.Spec.TargetRef; ref != nil {
+		return ref.Kind == targetKind(obj) && ref.Name == obj.GetName()
+	}
+	if override.Spec.Target != nil {
+		return targetSelects(*override.Spec.Target, obj)
+	}
+	return false
+}
+
+func validateOverride(spec *autoscalev1alpha1.ScaleOverrideSpec) error {
+	if (spec.TargetRef == nil) == (spec.Target == nil) {
+		return fmt.Errorf("exactly one of targetRef or target must be set")
+	}
+	if spec.Target != nil {
+		return validateSelectors(spec.Target.Selector)
+	}
+	return nil
+}
+
+// deleteExpiredOverride garbage-collects an override once it has expired.
+func (r *ScalerReconciler) deleteExpiredOverride(ctx context.Context, override *autoscalev1alpha1.ScaleOverride) {
+	log := ctrllog.FromContext(ctx)
+	if err := r.Client.Delete(ctx, override); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "Failed to delete expired ScaleOverride", "namespace", override.Namespace, "name", override.Name)
+		return
+	}
+	log.Info("Deleted expired ScaleOverride", "namespace", override.Namespace, "name", override.Name,
+		"requester", override.Spec.Requester, "reason", override.Spec.Reason)
+}
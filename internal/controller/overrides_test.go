@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+)
+
+func TestScaleOverrideTarget(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 22, 0, 0, 0, time.UTC) // Tuesday night
+	created := metav1.NewTime(now.Add(-2 * time.Hour))
+	sources := &scheduleSources{
+		nsAnnotations: map[string]map[string]string{
+			"team-a": {UptimeAnnotation: "Mon-Fri 08:00-20:00 UTC"},
+		},
+		overrides: []autoscalev1alpha1.ScaleOverride{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "team-a", CreationTimestamp: created},
+				Spec: autoscalev1alpha1.ScaleOverrideSpec{
+					TargetRef: &autoscalev1alpha1.TargetReference{Kind: autoscalev1alpha1.KindDeployment, Name: "api"},
+					State:     autoscalev1alpha1.OverrideUp,
+					ExpiresAt: metav1.NewTime(now.Add(time.Hour)),
+					Reason:    "customer demo",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "expired", Namespace: "team-a", CreationTimestamp: created},
+				Spec: autoscalev1alpha1.ScaleOverrideSpec{
+					TargetRef: &autoscalev1alpha1.TargetReference{Kind: autoscalev1alpha1.KindDeployment, Name: "worker"},
+					State:     autoscalev1alpha1.OverrideUp,
+					ExpiresAt: metav1.NewTime(now.Add(-time.Minute)),
+					Reason:    "load test",
+				},
+			},
+		},
+	}
+
+	api := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "api"}}
+	target := sources.targetFor(api, now)
+	assert.Equal(t, autoscalev1alpha1.OverrideUp, target.override)
+	inUptime, inDowntime := target.evaluate(MergeAnnotations(target.defaults, nil), now)
+	assert.True(t, inUptime, "override should force uptime outside the schedule")
+	assert.False(t, inDowntime)
+
+	worker := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "worker"}}
+	target = sources.targetFor(worker, now)
+	assert.Empty(t, target.override, "expired override should not apply")
+	inUptime, _ = target.evaluate(MergeAnnotations(target.defaults, nil), now)
+	assert.False(t, inUptime)
+}
+
+func TestInvalidOverrideForcesNothing(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, autoscalev1alpha1.AddToScheme(scheme))
+
+	invalid := &autoscalev1alpha1.ScaleOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "both", Namespace: "team-a"},
+		Spec: autoscalev1alpha1.ScaleOverrideSpec{
+			TargetRef: &autoscalev1alpha1.TargetReference{Kind: autoscalev1alpha1.KindDeployment, Name: "api"},
+			Target:    &autoscalev1alpha1.ScalerTarget{},
+			State:     autoscalev1alpha1.OverrideUp,
+			ExpiresAt: metav1.NewTime(time.Now().Add(time.Hour)),
+			Reason:    "customer demo",
+		},
+	}
+	r := &ScalerReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(invalid).Build()}
+	sources, err := r.loadSources(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, sources.overrides, "invalid override should not be loaded")
+
+	api := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "api"}}
+	assert.Empty(t, sources.targetFor(api, time.Now()).override, "invalid override should not force its state")
+}
+
+func TestValidateOverride(t *testing.T) {
+	ref := &autoscalev1alpha1.TargetReference{Kind: autoscalev1alpha1.KindDeployment, Name: "api"}
+	target := &autoscalev1alpha1.ScalerTarget{}
+
+	assert.NoError(t, validateOverride(&autoscalev1alpha1.ScaleOverrideSpec{TargetRef: ref}))
+	assert.NoError(t, validateOverride(&autoscalev1alpha1.ScaleOverrideSpec{Target: target}))
+	assert.Error(t, validateOverride(&autoscalev1alpha1.ScaleOverrideSpec{}))
+	assert.Error(t, validateOverride(&autoscalev1alpha1.ScaleOverrideSpec{TargetRef: ref, Target: target}))
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+	"github.com/cicd-toolkit/kubescale/internal/duration"
+	"github.com/cicd-toolkit/kubescale/internal/schedule"
+)
+
+// maxReportedViolations bounds the violations listed in a ScalePolicy status.
+const maxReportedViolations = 100
+
+// scalePolicy is a ScalePolicy ready for evaluation. A nil policy allows
+// everything.
+type scalePolicy struct {
+	name             string
+	spec             *autoscalev1alpha1.ScalePolicySpec
+	namespaces       labels.Selector
+	requiredDowntime []*schedule.Schedule
+	// maxExcludeUntil is the parsed MaxExcludeUntil, zero when unset.
+	maxExcludeUntil time.Duration
+}
+
+// newScalePolicy parses item, reading required downtime windows without a
+// timezone in loc.
+func newScalePolicy(item *autoscalev1alpha1.ScalePolicy, presets *PresetRegistry, loc *time.Location) (*scalePolicy, error) {
+	namespaces, err := metav1.LabelSelectorAsSelector(&item.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+	}
+	policy := &scalePolicy{name: item.Name, spec: &item.Spec, namespaces: namespaces}
+	for _, window := range item.Spec.RequiredDowntime {
+		parsed, err := schedule.Parse(window, presets, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid requiredDowntime %q: %w", window, err)
+		}
+		policy.requiredDowntime = append(policy.requiredDowntime, parsed)
+	}
+	if item.Spec.MaxExcludeUntil != "" {
+		if policy.maxExcludeUntil, err = duration.Parse(item.Spec.MaxExcludeUntil); err != nil {
+			return nil, fmt.Errorf("invalid maxExcludeUntil: %w", err)
+		}
+	}
+	return policy, nil
+}
+
+// clamps reports whether the policy is enforced rather than only reported.
+func (p *scalePolicy) clamps() bool {
+	return p != nil && p.spec.Enforcement == autoscalev1alpha1.PolicyClamp
+}
+
+func (p *scalePolicy) excludeAllowed() bool {
+	return p == nil || p.spec.AllowExclude == nil || *p.spec.AllowExclude
+}
+
+// inRequiredDowntime reports whether t falls in a required downtime window.
+func (p *scalePolicy) inRequiredDowntime(t time.Time) bool {
+	if p == nil {
+		return false
+	}
+	for _, s := range p.requiredDowntime {
+		if s.Active(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextRequiredDowntime returns when a required downtime window next starts
+// or ends after t.
+func (p *scalePolicy) nextRequiredDowntime(t time.Time) (time.Time, bool) {
+	var next time.Time
+	if p == nil {
+		return next, false
+	}
+	for _, s := range p.requiredDowntime {
+		if at, ok := s.NextTransition(t); ok && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// excludeUntilTooFar reports whether the kubescale/exclude-until of
+// annotations points further ahead of now than the policy allows.
+func (p *scalePolicy) excludeUntilTooFar(annotations map[string]string, now time.Time) (time.Time, bool) {
+	if p == nil || p.spec.MaxExcludeUntil == "" {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, annotations[ExcludeUntilAnnotation])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return until, until.After(now.Add(p.maxExcludeUntil))
+}
+
+// constrainExclusions returns own without the exclusions a clamping policy
+// forbids at now. own itself is left untouched.
+func (p *scalePolicy) constrainExclusions(own map[string]string, now time.Time) map[string]string {
+	if !p.clamps() {
+		return own
+	}
+	constrained := MergeAnnotations(nil, own)
+	if !p.excludeAllowed() {
+		delete(constrained, ExcludeAnnotation)
+		delete(constrained, ExcludeUntilAnnotation)
+	} else if _, tooFar := p.excludeUntilTooFar(own, now); tooFar {
+		delete(constrained, ExcludeUntilAnnotation)
+	}
+	return constrained
+}
+
+// violation is a rule broken by a workload.
+type violation struct {
+	rule    string
+	message string
+}
+
+// violations lists the rules broken by a workload whose own annotations are
+// own, whose effective annotations are annotations and on which override is
+// active.
+func (p *scalePolicy) violations(
+	presets *PresetRegistry,
+	loc *time.Location,
+	own, annotations map[string]string,
+	override autoscalev1alpha1.OverrideState,
+	now time.Time,
+) []violation {
+	var found []violation
+	if !p.excludeAllowed() {
+		if strings.ToLower(own[ExcludeAnnotation]) == "true" {
+			found = append(found, violation{autoscalev1alpha1.RuleExcludeNotAllowed, ExcludeAnnotation + " is not allowed"})
+		}
+		if _, ok := own[ExcludeUntilAnnotation]; ok {
+			found = append(found, violation{autoscalev1alpha1.RuleExcludeNotAllowed, ExcludeUntilAnnotation + " is not allowed"})
+		}
+		if override == autoscalev1alpha1.OverrideExcluded {
+			found = append(found, violation{autoscalev1alpha1.RuleExcludeNotAllowed, "Excluded overrides are not allowed"})
+		}
+	} else if until, tooFar := p.excludeUntilTooFar(own, now); tooFar {
+		found = append(found, violation{autoscalev1alpha1.RuleExcludeUntilHorizon, fmt.Sprintf(
+			"%s %s is more than %s ahead", ExcludeUntilAnnotation, until.Format(time.RFC3339), p.spec.MaxExcludeUntil)})
+	}
+
+	if p.spec.MaxWeeklyUptimeHours == nil && len(p.requiredDowntime) == 0 {
+		return found
+	}
+
+	// Walk the coming week from one transition to the next, with the
+	// windows parsed once
+	var uptime, downtime *schedule.Schedule
+	if val, ok := annotations[UptimeAnnotation]; ok {
+		uptime, _ = schedule.Parse(val, presets, loc)
+	}
+	if val, ok := annotations[DowntimeAnnotation]; ok {
+		downtime, _ = schedule.Parse(val, presets, loc)
+	}
+	changes := []func(time.Time) (time.Time, bool){uptime.NextTransition, downtime.NextTransition, p.nextRequiredDowntime}
+	var up time.Duration
+	var uncovered time.Time
+	start := now.Truncate(time.Minute)
+	end := start.Add(7 * 24 * time.Hour)
+	for at := start; at.Before(end); {
+		next := end
+		for _, change := range changes {
+			if c, ok := change(at); ok && c.Before(next) {
+				next = c
+			}
+		}
+		inDowntime := downtime.Active(at)
+		if !inDowntime && uptime.Active(at) {
+			up += next.Sub(at)
+		}
+		if uncovered.IsZero() && !inDowntime && p.inRequiredDowntime(at) {
+			uncovered = at
+		}
+		at = next
+	}
+
+	if limit := p.spec.MaxWeeklyUptimeHours; limit != nil && up > time.Duration(*limit)*time.Hour {
+		found = append(found, violation{autoscalev1alpha1.RuleMaxWeeklyUptime, fmt.Sprintf(
+			"scheduled up %s per week, at most %dh allowed", up, *limit)})
+	}
+	if !uncovered.IsZero() {
+		found = append(found, violation{autoscalev1alpha1.RuleRequiredDowntime, fmt.Sprintf(
+			"not scheduled down at %s, during required downtime", uncovered.UTC().Format(time.RFC3339))})
+	}
+	return found
+}
+
+// policyFor returns the first ScalePolicy by name that applies to namespace.
+func (s *scheduleSources) policyFor(namespace string) *scalePolicy {
+	for _, policy := range s.policies {
+		if policy.namespaces.Matches(labels.Set(s.nsLabels[namespace])) {
+			return policy
+		}
+	}
+	return nil
+}
+
+// reconcileScalePolicy lists the violations of a ScalePolicy in its status,
+// leaving out the workloads an earlier policy applies to, and reports those
+// with the Shadowed condition. Enforcement happens while the workloads are
+// evaluated.
+func (r *ScalerReconciler) reconcileScalePolicy(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := ctrllog.FromContext(ctx)
+
+	var item autoscalev1alpha1.ScalePolicy
+	if err := r.Get(ctx, req.NamespacedName, &item); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	policy, err := newScalePolicy(&item, r.Presets, r.Location)
+	if err != nil {
+		logger.Error(err, "Invalid ScalePolicy", "name", item.Name)
+		item.Status = autoscalev1alpha1.ScalePolicyStatus{
+			ObservedGeneration: item.Generation,
+			Conditions:         item.Status.Conditions,
+		}
+		apimeta.SetStatusCondition(&item.Status.Conditions, policyReadyCondition(err, item.Generation))
+		return ctrl.Result{}, r.Status().Update(ctx, &item)
+	}
+
+	sources, err := r.loadSources(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now().UTC()
+	status := autoscalev1alpha1.ScalePolicyStatus{
+		ObservedGeneration: item.Generation,
+		Conditions:         item.Status.Conditions,
+	}
+	shadowed := make(map[string]int)
+	for _, obj := range r.listTargets(ctx, "") {
+		if !policy.namespaces.Matches(labels.Set(sources.nsLabels[obj.GetNamespace()])) {
+			continue
+		}
+		if applied := sources.policyFor(obj.GetNamespace()); applied != nil && applied.name != policy.name {
+			shadowed[applied.name]++
+			continue
+		}
+		status.MatchedTargets++
+		target := sources.targetFor(obj, now)
+		own := obj.GetAnnotations()
+		for _, v := range policy.violations(sources.presets, target.location, own, MergeAnnotations(target.defaults, own), target.override, now) {
+			status.ViolationCount++
+			if len(status.Violations) < maxReportedViolations {
+				status.Violations = append(status.Violations, autoscalev1alpha1.PolicyViolation{
+					Namespace: obj.GetNamespace(),
+					Kind:      targetKind(obj),
+					Name:      obj.GetName(),
+					Rule:      v.rule,
+					Message:   v.message,
+				})
+			}
+		}
+	}
+
+	apimeta.SetStatusCondition(&status.Conditions, policyReadyCondition(nil, item.Generation))
+	apimeta.SetStatusCondition(&status.Conditions, shadowedCondition(shadowed, item.Generation))
+
+	item.Status = status
+	if err := r.Status().Update(ctx, &item); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: statusResyncPeriod}, nil
+}
+
+// policyReadyCondition reports whether the policy spec was parsed, with the
+// error that kept it from being parsed otherwise.
+func policyReadyCondition(policyErr error, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               autoscalev1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Parsed",
+		ObservedGeneration: generation,
+	}
+	if policyErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidPolicy"
+		condition.Message = policyErr.Error()
+	}
+	return condition
+}
+
+// shadowedCondition reports the workloads an earlier policy applies to
+// instead, counted by policy name.
+func shadowedCondition(shadowed map[string]int, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               autoscalev1alpha1.ConditionShadowed,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Applied",
+		ObservedGeneration: generation,
+	}
+	if len(shadowed) == 0 {
+		return condition
+	}
+	var names []string
+	for name := range shadowed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%d to %s", shadowed[name], name))
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "EarlierPolicy"
+	condition.Message = "policies earlier by name apply to targets it selects: " + strings.Join(parts, ", ")
+	return condition
+}
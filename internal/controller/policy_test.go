@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+)
+
+func newTestPolicy(t *testing.T, spec autoscalev1alpha1.ScalePolicySpec) *scalePolicy {
+	policy, err := newScalePolicy(&autoscalev1alpha1.ScalePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cost"},
+		Spec:       spec,
+	}, nil, nil)
+	assert.NoError(t, err)
+	return policy
+}
+
+// duringRequiredDowntime and outsideRequiredDowntime are a Wednesday inside
+// and outside the required downtime of newClampPolicy.
+var (
+	duringRequiredDowntime  = time.Date(2026, 5, 13, 1, 30, 0, 0, time.UTC)
+	outsideRequiredDowntime = time.Date(2026, 5, 13, 12, 0, 0, 0, time.UTC)
+)
+
+// newClampPolicy returns a clamping policy requiring downtime on Wednesdays
+// from 01:00 to 02:00 UTC.
+func newClampPolicy(t *testing.T) *scalePolicy {
+	return newTestPolicy(t, autoscalev1alpha1.ScalePolicySpec{
+		RequiredDowntime: []string{"Wed 01:00-02:00"},
+		Enforcement:      autoscalev1alpha1.PolicyClamp,
+	})
+}
+
+func TestScalePolicyViolations(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC) // Monday
+	noExclude := false
+	maxUptime := int32(60)
+	policy := newTestPolicy(t, autoscalev1alpha1.ScalePolicySpec{
+		MaxWeeklyUptimeHours: &maxUptime,
+		AllowExclude:         &noExclude,
+		RequiredDowntime:     []string{"Sat-Sun 00:00-23:59 UTC"},
+	})
+
+	tests := []struct {
+		name     string
+		own      map[string]string
+		override autoscalev1alpha1.OverrideState
+		rules    []string
+	}{
+		{
+			"compliant",
+			map[string]string{UptimeAnnotation: "Mon-Fri 08:00-18:00 UTC", DowntimeAnnotation: "Sat-Sun 00:00-23:59 UTC"},
+			"", nil,
+		},
+		{
+			"excluded",
+			map[string]string{ExcludeAnnotation: "true", DowntimeAnnotation: "Sat-Sun 00:00-23:59 UTC"},
+			"", []string{autoscalev1alpha1.RuleExcludeNotAllowed},
+		},
+		{
+			"excluded by override",
+			map[string]string{DowntimeAnnotation: "Sat-Sun 00:00-23:59 UTC"},
+			autoscalev1alpha1.OverrideExcluded, []string{autoscalev1alpha1.RuleExcludeNotAllowed},
+		},
+		{
+			"always up",
+			map[string]string{UptimeAnnotation: "00:00-23:59 UTC"},
+			"", []string{autoscalev1alpha1.RuleMaxWeeklyUptime, autoscalev1alpha1.RuleRequiredDowntime},
+		},
+	}
+
+	for _, test := range tests {
+		var rules []string
+		for _, v := range policy.violations(nil, nil, test.own, test.own, test.override, now) {
+			rules = append(rules, v.rule)
+		}
+		assert.Equal(t, test.rules, rules, "unexpected result for test case: %s", test.name)
+	}
+}
+
+func TestScalePolicyExcludeUntilHorizon(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	policy := newTestPolicy(t, autoscalev1alpha1.ScalePolicySpec{
+		MaxExcludeUntil: "7d",
+		Enforcement:     autoscalev1alpha1.PolicyClamp,
+	})
+
+	soon := map[string]string{ExcludeUntilAnnotation: "2025-06-05T10:00:00Z"}
+	late := map[string]string{ExcludeUntilAnnotation: "2025-07-01T10:00:00Z"}
+	assert.Empty(t, policy.violations(nil, nil, soon, soon, "", now))
+	if v := policy.violations(nil, nil, late, late, "", now); assert.Len(t, v, 1) {
+		assert.Equal(t, autoscalev1alpha1.RuleExcludeUntilHorizon, v[0].rule)
+	}
+
+	// Clamping drops the exclusion that goes too far
+	assert.Equal(t, soon, policy.constrainExclusions(soon, now))
+	assert.Empty(t, policy.constrainExclusions(late, now))
+	assert.Contains(t, late, ExcludeUntilAnnotation, "the workload annotations must not be modified")
+
+	_, err := newScalePolicy(&autoscalev1alpha1.ScalePolicy{Spec: autoscalev1alpha1.ScalePolicySpec{MaxExcludeUntil: "a week"}}, nil, nil)
+	assert.EqualError(t, err, `invalid maxExcludeUntil: invalid duration "a week", expected e.g. 90m, 1h30m, 1.5h, 2d or P1DT4H`)
+}
+
+func TestScalePolicyClamp(t *testing.T) {
+	weekday := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 6, 7, 10, 0, 0, 0, time.UTC)
+	noExclude := false
+	spec := autoscalev1alpha1.ScalePolicySpec{
+		AllowExclude:     &noExclude,
+		RequiredDowntime: []string{"Sat-Sun 00:00-23:59 UTC"},
+	}
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name: "api", Annotations: map[string]string{ExcludeAnnotation: "true"},
+	}}
+
+	// Reported only: the workload keeps its exclusion and its schedule
+	target := &scheduleTarget{sources: &scheduleSources{}, defaults: map[string]string{}, policy: newTestPolicy(t, spec)}
+	assert.True(t, target.skip(&deploy.ObjectMeta, weekday))
+	inUptime, inDowntime := target.evaluate(map[string]string{}, saturday)
+	assert.False(t, inUptime)
+	assert.False(t, inDowntime)
+
+	// Clamped: the exclusion is ignored and the required downtime is forced
+	spec.Enforcement = autoscalev1alpha1.PolicyClamp
+	target.policy = newTestPolicy(t, spec)
+	assert.False(t, target.skip(&deploy.ObjectMeta, weekday))
+	target.override = autoscalev1alpha1.OverrideUp
+	inUptime, inDowntime = target.evaluate(map[string]string{}, saturday)
+	assert.False(t, inUptime)
+	assert.True(t, inDowntime)
+	target.override = ""
+	inUptime, inDowntime = target.evaluate(map[string]string{}, weekday)
+	assert.False(t, inUptime, "a workload kubescale never scaled down is left alone")
+	assert.False(t, inDowntime)
+	inUptime, inDowntime = target.evaluate(map[string]string{PreviousReplicasAnnotation: "3"}, weekday)
+	assert.True(t, inUptime, "a workload scaled down for the required downtime is restored")
+	assert.False(t, inDowntime)
+}
+
+func TestScheduleSourcesPolicyFor(t *testing.T) {
+	dev := newTestPolicy(t, autoscalev1alpha1.ScalePolicySpec{
+		NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+	})
+	sources := &scheduleSources{
+		nsLabels: map[string]map[string]string{"team-a": {"env": "dev"}, "team-b": {"env": "prod"}},
+		policies: []*scalePolicy{dev},
+	}
+	assert.Equal(t, dev, sources.policyFor("team-a"))
+	assert.Nil(t, sources.policyFor("team-b"))
+}
+
+func TestScalePolicyWeeklyUptime(t *testing.T) {
+	now := time.Date(2025, 6, 4, 10, 30, 0, 0, time.UTC) // Wednesday
+	maxUptime := int32(50)
+	policy := newTestPolicy(t, autoscalev1alpha1.ScalePolicySpec{MaxWeeklyUptimeHours: &maxUptime})
+
+	own := map[string]string{UptimeAnnotation: "Mon-Fri 08:00-20:00 UTC", DowntimeAnnotation: "Wed 12:00-14:00 UTC"}
+	if v := policy.violations(nil, nil, own, own, "", now); assert.Len(t, v, 1) {
+		assert.Equal(t, "scheduled up 58h0m0s per week, at most 50h allowed", v[0].message)
+	}
+	own[DowntimeAnnotation] = "Mon-Fri 12:00-14:00 UTC"
+	assert.Empty(t, policy.violations(nil, nil, own, own, "", now))
+}
+
+func TestPolicyReadyCondition(t *testing.T) {
+	condition := policyReadyCondition(nil, 3)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, int64(3), condition.ObservedGeneration)
+
+	condition = policyReadyCondition(errors.New(`invalid requiredDowntime "Mon 25:00-26:00"`), 3)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "InvalidPolicy", condition.Reason)
+	assert.Equal(t, `invalid requiredDowntime "Mon 25:00-26:00"`, condition.Message)
+}
+
+func TestShadowedCondition(t *testing.T) {
+	condition := shadowedCondition(nil, 2)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, int64(2), condition.ObservedGeneration)
+
+	condition = shadowedCondition(map[string]int{"strict": 1, "cost": 3}, 2)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "policies earlier by name apply to targets it selects: 3 to cost, 1 to strict", condition.Message)
+}
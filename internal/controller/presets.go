@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/cicd-toolkit/kubescale/internal/schedule"
+)
+
+// PresetLabel selects a schedule preset as the uptime of a workload or of
+// every workload in a namespace.
+const PresetLabel = "kubescale.io/schedule"
+
+// PresetRegistry holds the named schedules that kubescale/uptime and
+// kubescale/downtime can reference as "@name". It is safe for concurrent use
+// and a nil registry has no presets.
+type PresetRegistry struct {
+	mu      sync.RWMutex
+	presets map[string][]string
+}
+
+// NewPresetRegistry returns an empty registry.
+func NewPresetRegistry() *PresetRegistry {
+	return &PresetRegistry{presets: make(map[string][]string)}
+}
+
+// Load replaces the presets with data, which maps a preset name to its
+// windows, one per line. Invalid presets are left out and reported.
+func (p *PresetRegistry) Load(data map[string]string) error {
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	presets := make(map[string][]string, len(data))
+	var errs []error
+	for _, name := range names {
+		windows, err := parsePreset(data[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("preset %q: %w", name, err))
+			continue
+		}
+		presets[name] = windows
+	}
+
+	p.mu.Lock()
+	p.presets = presets
+	p.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// parsePreset splits a preset into its windows and checks each of them.
+func parsePreset(value string) ([]string, error) {
+	windows := schedule.Split(value)
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("no windows")
+	}
+	for _, window := range windows {
+		if strings.HasPrefix(window, schedule.PresetPrefix) {
+			return nil, fmt.Errorf("presets cannot reference other presets")
+		}
+	}
+	if err := schedule.Validate(value); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// Lookup returns the windows of the named preset.
+func (p *PresetRegistry) Lookup(name string) ([]string, bool) {
+	if p == nil {
+		return nil, false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	windows, ok := p.presets[name]
+	return windows, ok
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// PresetReconciler keeps a PresetRegistry in sync with a ConfigMap, so that
+// editing the ConfigMap takes effect without restarting the controller.
+type PresetReconciler struct {
+	client.Client
+	Registry  *PresetRegistry
+	ConfigMap types.NamespacedName
+}
+
+// Reconcile reloads the registry from the ConfigMap. A missing ConfigMap
+// empties the registry.
+func (r *PresetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx)
+
+	var cm corev1.ConfigMap
+	if err := r.Get(ctx, r.ConfigMap, &cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		log.Info("Preset ConfigMap not found", "configmap", r.ConfigMap)
+	}
+
+	if err := r.Registry.Load(cm.Data); err != nil {
+		log.Error(err, "Invalid schedule presets", "configmap", r.ConfigMap)
+	}
+	log.Info("Loaded schedule presets", "configmap", r.ConfigMap)
+	return ctrl.Result{}, nil
+}
+
+// PresetCache restricts the ConfigMaps the manager caches to configMap, so
+// watching it does not list and cache every ConfigMap in the cluster. Other
+// ConfigMaps must be read with the manager's API reader.
+func PresetCache(configMap types.NamespacedName) map[client.Object]cache.ByObject {
+	return map[client.Object]cache.ByObject{
+		&corev1.ConfigMap{}: {
+			Namespaces: map[string]cache.Config{configMap.Namespace: {}},
+			Field:      fields.OneTermEqualSelector("metadata.name", configMap.Name),
+		},
+	}
+}
+
+// SetupWithManager watches the preset ConfigMap only. The manager cache
+// should be restricted to it with PresetCache.
+func (r *PresetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("presets").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetNamespace() == r.ConfigMap.Namespace && obj.GetName() == r.ConfigMap.Name
+		}))).
+		Complete(r)
+}
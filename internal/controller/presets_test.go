@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	"github.com/cicd-toolkit/kubescale/internal/schedule"
+)
+
+func TestPresetRegistryLoad(t *testing.T) {
+	presets := NewPresetRegistry()
+	err := presets.Load(map[string]string{
+		"office-hours-eu": "Mon-Fri 08:00-12:00 Europe/Paris\nMon-Fri 13:00-18:00 Europe/Paris\n",
+		"broken":          "Mon-Fir 08:00-18:00",
+		"nested":          "@office-hours-eu",
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `preset "broken": column 5: unknown day "Fir"`)
+		assert.Contains(t, err.Error(), `preset "nested": presets cannot reference other presets`)
+	}
+
+	windows, ok := presets.Lookup("office-hours-eu")
+	assert.True(t, ok)
+	assert.Len(t, windows, 2)
+	_, ok = presets.Lookup("broken")
+	assert.False(t, ok)
+
+	// Reloading replaces every preset
+	assert.NoError(t, presets.Load(map[string]string{"nights": "00:00-06:00"}))
+	_, ok = presets.Lookup("office-hours-eu")
+	assert.False(t, ok)
+
+	var none *PresetRegistry
+	_, ok = none.Lookup("nights")
+	assert.False(t, ok)
+}
+
+func TestPresetRegistryParseSchedule(t *testing.T) {
+	presets := NewPresetRegistry()
+	assert.NoError(t, presets.Load(map[string]string{
+		"office-hours": "Mon-Fri 08:00-12:00 UTC\nMon-Fri 13:00-18:00 UTC",
+	}))
+
+	tests := []struct {
+		value    string
+		at       time.Time
+		expected bool
+	}{
+		{"@office-hours", time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC), true},
+		{"@office-hours", time.Date(2025, 6, 2, 12, 30, 0, 0, time.UTC), false},
+		{"@office-hours", time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC), true},
+		{"Mon-Fri 08:00-12:00 UTC", time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC), false},
+		{"Mon-Fri 08:00-12:00 UTC, Mon-Fri 13:00-18:00 UTC", time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC), true},
+		{"Mon-Fri 08:00-12:00 UTC, Mon-Fri 13:00-18:00 UTC", time.Date(2025, 6, 2, 12, 30, 0, 0, time.UTC), false},
+		{"Mon-Fri 08:00-18:00 UTC, Sat-Sat 10:00-12:00 Europe/Paris", time.Date(2025, 6, 7, 9, 0, 0, 0, time.UTC), true},
+		{"@office-hours, Sat-Sat 10:00-12:00 UTC", time.Date(2025, 6, 7, 11, 0, 0, 0, time.UTC), true},
+	}
+	for _, test := range tests {
+		parsed, err := schedule.Parse(test.value, presets, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, parsed.Active(test.at), "unexpected result for %s at %s", test.value, test.at)
+	}
+
+	_, err := schedule.Parse("@unknown", presets, nil)
+	assert.EqualError(t, err, `unknown preset "unknown"`)
+
+	// A malformed window fails the whole value and is named in the error
+	_, err = schedule.Parse("Mon-Fri 08:00-12:00 UTC, weekends", presets, nil)
+	assert.EqualError(t, err, `window 2 "weekends": column 1: unknown day "weekends", expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun`)
+}
+
+func TestPresetLabel(t *testing.T) {
+	sources := &scheduleSources{
+		nsLabels: map[string]map[string]string{"team-a": {PresetLabel: "office-hours-eu"}},
+	}
+
+	// The namespace label applies to every workload of the namespace
+	defaults := sources.defaultsFor(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "api"}})
+	assert.Equal(t, "@office-hours-eu", defaults[UptimeAnnotation])
+
+	// The workload label takes priority over it
+	defaults = sources.defaultsFor(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "team-a", Name: "api", Labels: map[string]string{PresetLabel: "office-hours-us"},
+	}})
+	assert.Equal(t, "@office-hours-us", defaults[UptimeAnnotation])
+
+	// Namespace annotations take priority over the namespace label
+	sources.nsAnnotations = map[string]map[string]string{"team-a": {UptimeAnnotation: "Mon-Fri 07:00-19:00 UTC"}}
+	defaults = sources.defaultsFor(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "api"}})
+	assert.Equal(t, "Mon-Fri 07:00-19:00 UTC", defaults[UptimeAnnotation])
+}
+
+func TestPresetCache(t *testing.T) {
+	byObject := PresetCache(types.NamespacedName{Namespace: "kubescale", Name: "presets"})
+	if assert.Len(t, byObject, 1) {
+		for obj, config := range byObject {
+			assert.IsType(t, &corev1.ConfigMap{}, obj)
+			assert.Equal(t, map[string]cache.Config{"kubescale": {}}, config.Namespaces)
+			assert.True(t, config.Field.Matches(fields.Set{"metadata.name": "presets"}))
+			assert.False(t, config.Field.Matches(fields.Set{"metadata.name": "kube-root-ca.crt"}))
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cicd-toolkit/kubescale/internal/schedule"
+)
+
+// replicaProfile is a schedule and the replica count targets run at while it
+// is active.
+type replicaProfile struct {
+	replicas replicaTarget
+	schedule *schedule.Schedule
+}
+
+// parseProfiles parses a kubescale/profiles value: "<replicas>: <schedule>"
+// entries separated by semicolons or newlines, such as
+// "6: Mon-Fri 08:00-18:00; 25%: Mon-Fri 18:00-22:00". Schedules use the
+// kubescale/uptime syntax, read in loc without a timezone.
+func parseProfiles(value string, presets schedule.Presets, loc *time.Location) ([]replicaProfile, error) {
+	entries := splitProfiles(value)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("invalid format %q, expected \"<replicas>: <schedule>; ...\"", value)
+	}
+	profiles := make([]replicaProfile, len(entries))
+	for i, entry := range entries {
+		replicas, text, err := parseProfile(entry)
+		if err == nil {
+			profiles[i].schedule, err = schedule.Parse(text, presets, loc)
+		}
+		if err != nil {
+			return nil, profileError(entries, i, err)
+		}
+		profiles[i].replicas = replicas
+	}
+	return profiles, nil
+}
+
+// validateProfiles checks a kubescale/profiles value like parseProfiles,
+// without resolving preset references.
+func validateProfiles(value string) error {
+	entries := splitProfiles(value)
+	if len(entries) == 0 {
+		return fmt.Errorf("invalid format %q, expected \"<replicas>: <schedule>; ...\"", value)
+	}
+	for i, entry := range entries {
+		_, text, err := parseProfile(entry)
+		if err == nil {
+			err = schedule.Validate(text)
+		}
+		if err != nil {
+			return profileError(entries, i, err)
+		}
+	}
+	return nil
+}
+
+// parseProfile splits a profile entry into its replica count and schedule.
+func parseProfile(entry string) (replicaTarget, string, error) {
+	count, text, ok := strings.Cut(entry, ":")
+	replicas, err := parseReplicaTarget(strings.TrimSpace(count))
+	if !ok || err != nil {
+		return replicaTarget{}, "", fmt.Errorf("invalid replica count %q, expected \"<replicas>: <schedule>\"", strings.TrimSpace(count))
+	}
+	return replicas, strings.TrimSpace(text), nil
+}
+
+// splitProfiles splits a kubescale/profiles value into its entries. Semicolons
+// inside parentheses belong to the schedule, as in cron windows.
+func splitProfiles(value string) []string {
+	var entries []string
+	depth, start := 0, 0
+	for i, r := range value + "\n" {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case (r == ';' && depth == 0) || r == '\n':
+			if entry := strings.TrimSpace(value[start:min(i, len(value))]); entry != "" {
+				entries = append(entries, entry)
+			}
+			start = i + 1
+		}
+	}
+	return entries
+}
+
+// profileError names the offending entry of a value holding several.
+func profileError(entries []string, i int, err error) error {
+	if len(entries) == 1 {
+		return err
+	}
+	return fmt.Errorf("profile %d %q: %w", i+1, entries[i], err)
+}
+
+// profiles parses the kubescale/profiles of annotations. A missing or
+// malformed value has none.
+func (t *scheduleTarget) profiles(annotations map[string]string) []replicaProfile {
+	val, ok := annotations[ProfilesAnnotation]
+	if !ok {
+		return nil
+	}
+	profiles, _ := parseProfiles(val, t.sources.presets, t.location)
+	return profiles
+}
+
+// profile returns the replica count of the first profile of annotations
+// active at now, and false when none is, or when downtime or a forced state
+// decides instead.
+func (t *scheduleTarget) profile(annotations map[string]string, now time.Time) (replicaTarget, bool) {
+	profiles := t.profiles(annotations)
+	if len(profiles) == 0 {
+		return replicaTarget{}, false
+	}
+	if _, _, forced := t.forced(annotations, now); forced {
+		return replicaTarget{}, false
+	}
+	if t.policy.clamps() && t.policy.inRequiredDowntime(now) {
+		return replicaTarget{}, false
+	}
+	if _, downtime := t.schedules(annotations); downtime.Active(now) {
+		return replicaTarget{}, false
+	}
+	for _, p := range profiles {
+		if p.schedule.Active(now) {
+			return p.replicas, true
+		}
+	}
+	return replicaTarget{}, false
+}
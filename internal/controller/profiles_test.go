@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+)
+
+func TestParseProfiles(t *testing.T) {
+	profiles, err := parseProfiles("6: Mon-Fri 08:00-18:00; 25%: Mon-Fri 18:00-22:00\n0: cron(0 22 * * 1-5; 0 8 * * 1-5)", nil, nil)
+	if assert.NoError(t, err) && assert.Len(t, profiles, 3) {
+		assert.Equal(t, []replicaTarget{{count: 6}, {percent: 25, relative: true}, {}},
+			[]replicaTarget{profiles[0].replicas, profiles[1].replicas, profiles[2].replicas})
+	}
+
+	tests := []struct {
+		value    string
+		errorMsg string
+	}{
+		{" ; ", `invalid format " ; "`},
+		{"Mon-Fri 08:00-18:00", `invalid replica count "Mon-Fri 08"`},
+		{"-1: 08:00-18:00", `invalid replica count "-1"`},
+		{"-25%: 08:00-18:00", `invalid replica count "-25%"`},
+		{"6: Mon-Fri 08:00-18:00; 2: Mon-Fir 18:00-22:00", `profile 2 "2: Mon-Fir 18:00-22:00": column 5: unknown day "Fir"`},
+	}
+	for _, test := range tests {
+		_, err := parseProfiles(test.value, nil, nil)
+		if assert.Error(t, err, "expected an error for value: %s", test.value) {
+			assert.Contains(t, err.Error(), test.errorMsg)
+		}
+		assert.Error(t, validateProfiles(test.value), "expected a validation error for value: %s", test.value)
+	}
+	assert.NoError(t, validateProfiles("2: @evening"))
+}
+
+func TestScheduleTargetProfile(t *testing.T) {
+	target := &scheduleTarget{sources: &scheduleSources{}, location: time.UTC}
+	annotations := map[string]string{
+		ProfilesAnnotation: "6: Mon-Fri 08:00-18:00; 2: Mon-Fri 18:00-22:00; 3: Mon-Fri 17:00-19:00",
+		DowntimeAnnotation: "Sat-Sun 00:00-23:59",
+	}
+	monday := func(hour int) time.Time { return time.Date(2025, 6, 2, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		override autoscalev1alpha1.OverrideState
+		replicas int32
+		active   bool
+	}{
+		{"office hours", monday(10), "", 6, true},
+		{"first profile wins", monday(17), "", 6, true},
+		{"evening", monday(20), "", 2, true},
+		{"outside every profile", monday(23), "", 0, false},
+		{"downtime wins", time.Date(2025, 6, 7, 10, 0, 0, 0, time.UTC), "", 0, false},
+		{"override wins", monday(10), autoscalev1alpha1.OverrideUp, 0, false},
+	}
+	for _, test := range tests {
+		target.override = test.override
+		replicas, active := target.profile(annotations, test.now)
+		assert.Equal(t, test.active, active, "unexpected activity for test case: %s", test.name)
+		assert.Equal(t, replicaTarget{count: test.replicas}, replicas, "unexpected replicas for test case: %s", test.name)
+	}
+
+	// Moving from one profile to the next is a transition
+	target.override = ""
+	next := target.nextTransition(annotations, monday(10))
+	if assert.NotNil(t, next) {
+		assert.Equal(t, monday(18), next.UTC())
+	}
+}
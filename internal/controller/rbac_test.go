@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// templateLine matches the lines of a Helm template that are only a
+	// directive, and templateValue any other directive.
+	templateLine  = regexp.MustCompile(`(?m)^\s*\{\{.*\}\}\s*$`)
+	templateValue = regexp.MustCompile(`\{\{.*?\}\}`)
+)
+
+// grants returns every group/resource/verb triple rules allow.
+func grants(rules []rbacv1.PolicyRule) map[string]bool {
+	granted := make(map[string]bool)
+	for _, rule := range rules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				for _, verb := range rule.Verbs {
+					granted[group+"/"+resource+":"+verb] = true
+				}
+			}
+		}
+	}
+	return granted
+}
+
+func readClusterRole(t *testing.T, path string) rbacv1.ClusterRole {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var role rbacv1.ClusterRole
+	require.NoError(t, yaml.Unmarshal(templateValue.ReplaceAll(templateLine.ReplaceAll(data, nil), []byte("template")), &role), "reading %s", path)
+	return role
+}
+
+// The chart maintains its ClusterRole by hand, so it must keep granting
+// everything the RBAC markers generate into config/rbac.
+func TestChartClusterRoleCoversManagerRole(t *testing.T) {
+	generated := readClusterRole(t, "../../config/rbac/role.yaml")
+	chart := grants(readClusterRole(t, "../../chart/kubescale/templates/clusterrole.yaml").Rules)
+	require.NotEmpty(t, generated.Rules)
+	for grant := range grants(generated.Rules) {
+		assert.True(t, chart[grant], "chart ClusterRole does not grant %s", grant)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+)
+
+// replicaTarget is a replica count, either absolute or a percentage of the
+// count a resource had before kubescale scaled it.
+type replicaTarget struct {
+	count   int32
+	percent float64
+	// relative marks a percentage.
+	relative bool
+}
+
+// parseReplicaTarget parses a replica count such as "3" or a percentage
+// such as "25%".
+func parseReplicaTarget(value string) (replicaTarget, error) {
+	if number, ok := strings.CutSuffix(value, "%"); ok {
+		p, err := strconv.ParseFloat(number, 64)
+		if err != nil || p < 0 || math.IsInf(p, 0) || math.IsNaN(p) {
+			return replicaTarget{}, fmt.Errorf("invalid replica count %q, expected a non-negative integer or a percentage such as \"25%%\"", value)
+		}
+		return replicaTarget{percent: p, relative: true}, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || n < 0 {
+		return replicaTarget{}, fmt.Errorf("invalid replica count %q, expected a non-negative integer or a percentage such as \"25%%\"", value)
+	}
+	return replicaTarget{count: int32(n)}, nil
+}
+
+// zero reports whether t scales a resource all the way down, rounding and
+// minimums aside.
+func (t replicaTarget) zero() bool {
+	return t.count == 0 && t.percent == 0
+}
+
+// intOrString returns t as written in a ScalerSpec.
+func (t replicaTarget) intOrString() *intstr.IntOrString {
+	v := intstr.FromInt32(t.count)
+	if t.relative {
+		v = intstr.FromString(strconv.FormatFloat(t.percent, 'f', -1, 64) + "%")
+	}
+	return &v
+}
+
+// resolve returns the count of t for a resource that had before replicas.
+// A percentage is always taken of before, never of a count kubescale already
+// reduced, rounded as annotations ask with kubescale/replicas-rounding and
+// raised to their kubescale/min-replicas.
+func (t replicaTarget) resolve(annotations map[string]string, before int32) int32 {
+	if !t.relative {
+		return t.count
+	}
+	// Drop the float noise of exact results such as 10 * 30%
+	exact := math.Round(float64(before)*t.percent/100*1e6) / 1e6
+	var n float64
+	switch replicaRounding(annotations) {
+	case autoscalev1alpha1.ReplicaRoundingDown:
+		n = math.Floor(exact)
+	case autoscalev1alpha1.ReplicaRoundingNearest:
+		n = math.Round(exact)
+	default:
+		n = math.Ceil(exact)
+	}
+	return max(int32(min(n, math.MaxInt32)), minReplicas(annotations))
+}
+
+// replicasBefore returns the count a resource now at current replicas had
+// before kubescale scaled it, as saved in kubescale/previous-replicas.
+func replicasBefore(annotations map[string]string, current int32) int32 {
+	prev, err := strconv.Atoi(annotations[PreviousReplicasAnnotation])
+	if err != nil || prev < 0 {
+		return current
+	}
+	return int32(prev)
+}
+
+// downtimeCount returns the replica count downtime asks of a resource now at
+// current replicas, which is never above the count it had before.
+func downtimeCount(annotations map[string]string, current int32) int32 {
+	before := replicasBefore(annotations, current)
+	return min(downtimeReplicas(annotations).resolve(annotations, before), before)
+}
+
+// downtimeReplicas returns the replica count to keep during downtime, taken
+// from kubescale/replicas. It defaults to 0 when unset or invalid.
+func downtimeReplicas(annotations map[string]string) replicaTarget {
+	target, _ := parseReplicaTarget(annotations[CustomReplicaAnnotation])
+	return target
+}
+
+// replicaRounding returns the kubescale/replicas-rounding of annotations,
+// Up when unset or invalid.
+func replicaRounding(annotations map[string]string) autoscalev1alpha1.ReplicaRounding {
+	for _, r := range []autoscalev1alpha1.ReplicaRounding{
+		autoscalev1alpha1.ReplicaRoundingDown, autoscalev1alpha1.ReplicaRoundingNearest,
+	} {
+		if strings.EqualFold(annotations[ReplicaRoundingAnnotation], string(r)) {
+			return r
+		}
+	}
+	return autoscalev1alpha1.ReplicaRoundingUp
+}
+
+// minReplicas returns the kubescale/min-replicas of annotations, 0 when
+// unset or invalid.
+func minReplicas(annotations map[string]string) int32 {
+	n, err := strconv.ParseInt(annotations[MinReplicasAnnotation], 10, 32)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return int32(n)
+}
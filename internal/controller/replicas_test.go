@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReplicaTarget(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected replicaTarget
+	}{
+		{"0", replicaTarget{}},
+		{"3", replicaTarget{count: 3}},
+		{"25%", replicaTarget{percent: 25, relative: true}},
+		{"12.5%", replicaTarget{percent: 12.5, relative: true}},
+		{"0%", replicaTarget{relative: true}},
+	}
+	for _, test := range tests {
+		result, err := parseReplicaTarget(test.value)
+		if assert.NoError(t, err, "did not expect an error for value: %s", test.value) {
+			assert.Equal(t, test.expected, result, "unexpected result for value: %s", test.value)
+			assert.Equal(t, test.value, result.intOrString().String(), "unexpected string for value: %s", test.value)
+		}
+	}
+
+	for _, value := range []string{"", "-1", "three", "%", "-25%", "25 %", "Inf%", "NaN%", "3000000000"} {
+		_, err := parseReplicaTarget(value)
+		assert.Error(t, err, "expected an error for value: %s", value)
+	}
+}
+
+func TestReplicaTargetResolve(t *testing.T) {
+	tests := []struct {
+		value       string
+		annotations map[string]string
+		before      int32
+		expected    int32
+	}{
+		{"2", nil, 10, 2},
+		{"2", map[string]string{MinReplicasAnnotation: "3"}, 10, 2},
+		{"25%", nil, 4, 1},
+		{"25%", nil, 5, 2},
+		{"30%", nil, 10, 3},
+		{"25%", map[string]string{ReplicaRoundingAnnotation: "Down"}, 5, 1},
+		{"25%", map[string]string{ReplicaRoundingAnnotation: "nearest"}, 5, 1},
+		{"25%", map[string]string{ReplicaRoundingAnnotation: "nearest"}, 6, 2},
+		{"25%", map[string]string{ReplicaRoundingAnnotation: "sideways"}, 5, 2},
+		{"10%", map[string]string{ReplicaRoundingAnnotation: "down", MinReplicasAnnotation: "1"}, 5, 1},
+		{"0%", map[string]string{MinReplicasAnnotation: "2"}, 5, 2},
+		{"150%", nil, 4, 6},
+	}
+	for _, test := range tests {
+		target, err := parseReplicaTarget(test.value)
+		if assert.NoError(t, err) {
+			assert.Equal(t, test.expected, target.resolve(test.annotations, test.before),
+				"unexpected replicas for %s of %d with %v", test.value, test.before, test.annotations)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+)
+
+// groupPollInterval is how often a ScaleGroup is reconciled while members
+// wait for their dependencies.
+const groupPollInterval = 15 * time.Second
+
+// scaleGroup is a ScaleGroup whose dependency graph has been checked.
+// Members are keyed by Kind/name, so a Deployment and a StatefulSet may share
+// a name.
+type scaleGroup struct {
+	namespace  string
+	name       string
+	members    map[string]*autoscalev1alpha1.ScaleGroupMember
+	dependsOn  map[string][]string
+	dependents map[string][]string
+	// tiers lists the member keys in wake-up order.
+	tiers [][]string
+}
+
+// memberKey returns the Kind/name key of a member.
+func memberKey(kind autoscalev1alpha1.TargetKind, name string) string {
+	return string(kind) + "/" + name
+}
+
+// newScaleGroup checks that the members of item are unique, only depend on
+// each other and have no cycle, and sorts them into tiers.
+func newScaleGroup(item *autoscalev1alpha1.ScaleGroup) (*scaleGroup, error) {
+	group := &scaleGroup{
+		namespace:  item.Namespace,
+		name:       item.Name,
+		members:    make(map[string]*autoscalev1alpha1.ScaleGroupMember),
+		dependsOn:  make(map[string][]string),
+		dependents: make(map[string][]string),
+	}
+	byName := make(map[string][]string)
+	for i := range item.Spec.Members {
+		member := &item.Spec.Members[i]
+		key := memberKey(member.Kind, member.Name)
+		if _, ok := group.members[key]; ok {
+			return nil, fmt.Errorf("duplicate member %q", key)
+		}
+		group.members[key] = member
+		byName[member.Name] = append(byName[member.Name], key)
+	}
+
+	pending := make(map[string]int, len(group.members))
+	for key, member := range group.members {
+		for _, dep := range member.DependsOn {
+			depKey, err := group.resolve(dep, byName)
+			if err != nil {
+				return nil, fmt.Errorf("member %q %w", key, err)
+			}
+			group.dependsOn[key] = append(group.dependsOn[key], depKey)
+			group.dependents[depKey] = append(group.dependents[depKey], key)
+		}
+		pending[key] = len(member.DependsOn)
+	}
+
+	// Kahn's algorithm, one tier at a time
+	for len(pending) > 0 {
+		var tier []string
+		for name, deps := range pending {
+			if deps == 0 {
+				tier = append(tier, name)
+			}
+		}
+		if len(tier) == 0 {
+			var cycle []string
+			for name := range pending {
+				cycle = append(cycle, name)
+			}
+			sort.Strings(cycle)
+			return nil, fmt.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+		}
+		sort.Strings(tier)
+		for _, key := range tier {
+			delete(pending, key)
+			for _, dependent := range group.dependents[key] {
+				pending[dependent]--
+			}
+		}
+		group.tiers = append(group.tiers, tier)
+	}
+	return group, nil
+}
+
+// resolve returns the key of the member a dependsOn entry names: either its
+// Kind/name key, or its bare name when no other member shares it.
+func (g *scaleGroup) resolve(dep string, byName map[string][]string) (string, error) {
+	if _, ok := g.members[dep]; ok {
+		return dep, nil
+	}
+	switch keys := byName[dep]; len(keys) {
+	case 0:
+		return "", fmt.Errorf("depends on unknown member %q", dep)
+	case 1:
+		return keys[0], nil
+	default:
+		sort.Strings(keys)
+		return "", fmt.Errorf("depends on ambiguous member %q, use one of %s", dep, strings.Join(keys, ", "))
+	}
+}
+
+// keyOf returns the key of the member obj is, if any.
+func (g *scaleGroup) keyOf(obj client.Object) (string, bool) {
+	if obj.GetNamespace() != g.namespace {
+		return "", false
+	}
+	key := memberKey(targetKind(obj), obj.GetName())
+	if _, ok := g.members[key]; !ok {
+		return "", false
+	}
+	return key, true
+}
+
+// desired returns the state the schedule asks for at now, or an empty phase
+// when it asks for no change.
+func (t *scheduleTarget) desired(obj client.Object, now time.Time) autoscalev1alpha1.TargetPhase {
+	own := obj.GetAnnotations()
+	if t.skip(&metav1.ObjectMeta{Annotations: own}, now) {
+		return autoscalev1alpha1.TargetPhaseExcluded
+	}
+	switch inUptime, inDowntime := t.evaluate(MergeAnnotations(t.defaults, own), now); {
+	case inDowntime:
+		return autoscalev1alpha1.TargetPhaseDown
+	case inUptime:
+		return autoscalev1alpha1.TargetPhaseUp
+	}
+	return ""
+}
+
+// heldMembers returns the group members among objs that must not change
+// state yet, with the reason. A member waking up waits until the members it
+// depends on are ready, and a member going to sleep waits until the members
+// depending on it are down.
+func (s *scheduleSources) heldMembers(objs []client.Object, now time.Time) map[client.Object]string {
+	held := make(map[client.Object]string)
+	for _, group := range s.groups {
+		found := make(map[string]client.Object)
+		targets := make(map[string]*scheduleTarget)
+		desired := make(map[string]autoscalev1alpha1.TargetPhase)
+		for _, obj := range objs {
+			if key, ok := group.keyOf(obj); ok {
+				found[key] = obj
+				targets[key] = s.targetFor(obj, now)
+				desired[key] = targets[key].desired(obj, now)
+			}
+		}
+
+		for key, obj := range found {
+			switch desired[key] {
+			case autoscalev1alpha1.TargetPhaseUp:
+				for _, dep := range group.dependsOn[key] {
+					if other, ok := found[dep]; ok && desired[dep] == autoscalev1alpha1.TargetPhaseUp && !workloadReady(other) {
+						held[obj] = fmt.Sprintf("waiting for %s to be ready", dep)
+						break
+					}
+				}
+			case autoscalev1alpha1.TargetPhaseDown:
+				for _, dependent := range group.dependents[key] {
+					if other, ok := found[dependent]; ok && desired[dependent] == autoscalev1alpha1.TargetPhaseDown && !targets[dependent].workloadDown(other) {
+						held[obj] = fmt.Sprintf("waiting for %s to be down", dependent)
+						break
+					}
+				}
+			}
+		}
+	}
+	return held
+}
+
+// workloadReady reports whether obj runs and all its pods are ready.
+func workloadReady(obj client.Object) bool {
+	if wasScaledDown(obj.GetAnnotations()) {
+		return false
+	}
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return o.Status.ObservedGeneration >= o.Generation && o.Status.ReadyReplicas >= specReplicas(o.Spec.Replicas)
+	case *appsv1.StatefulSet:
+		return o.Status.ObservedGeneration >= o.Generation && o.Status.ReadyReplicas >= specReplicas(o.Spec.Replicas)
+	case *appsv1.DaemonSet:
+		return o.Status.ObservedGeneration >= o.Generation && o.Status.NumberReady >= o.Status.DesiredNumberScheduled
+	case *batchv1.CronJob:
+		return o.Spec.Suspend == nil || !*o.Spec.Suspend
+	case *unstructured.Unstructured:
+		replicas, found, _ := unstructured.NestedInt64(o.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		available, _, _ := unstructured.NestedInt64(o.Object, "status", "availableReplicas")
+		return unstructuredObserved(o) && available >= replicas
+	}
+	return true
+}
+
+// unstructuredObserved reports whether the operator of a custom resource has
+// seen its latest spec. Resources without status.observedGeneration count as
+// observed.
+func unstructuredObserved(o *unstructured.Unstructured) bool {
+	observed, found, _ := unstructured.NestedInt64(o.Object, "status", "observedGeneration")
+	return !found || observed >= o.GetGeneration()
+}
+
+// workloadDown reports whether obj is at the replica count downtime asks
+// for, or suspended, and its extra pods are gone. It goes by what is
+// observed, so a workload that was already down counts as well.
+func (t *scheduleTarget) workloadDown(obj client.Object) bool {
+	annotations := MergeAnnotations(t.defaults, obj.GetAnnotations())
+	scaled := func(replicas int32) bool {
+		return replicas <= downtimeCount(annotations, replicas)
+	}
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		replicas := specReplicas(o.Spec.Replicas)
+		return scaled(replicas) && o.Status.ObservedGeneration >= o.Generation && o.Status.Replicas <= replicas
+	case *appsv1.StatefulSet:
+		replicas := specReplicas(o.Spec.Replicas)
+		return scaled(replicas) && o.Status.ObservedGeneration >= o.Generation && o.Status.Replicas <= replicas
+	case *appsv1.DaemonSet:
+		return o.Status.ObservedGeneration >= o.Generation && o.Status.CurrentNumberScheduled == 0
+	case *batchv1.CronJob:
+		return o.Spec.Suspend != nil && *o.Spec.Suspend
+	case *unstructured.Unstructured:
+		replicas, found, _ := unstructured.NestedInt64(o.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		current, _, _ := unstructured.NestedInt64(o.Object, "status", "replicas")
+		return scaled(int32(replicas)) && unstructuredObserved(o) && current <= replicas
+	}
+	return true
+}
+
+// specReplicas returns the replica count of a spec, which defaults to 1.
+func specReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// reconcileScaleGroup drives the members of a ScaleGroup through their
+// transitions, coming back often while some of them wait for others.
+func (r *ScalerReconciler) reconcileScaleGroup(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := ctrllog.FromContext(ctx)
+
+	var item autoscalev1alpha1.ScaleGroup
+	if err := r.Get(ctx, req.NamespacedName, &item); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	group, err := newScaleGroup(&item)
+	if err != nil {
+		logger.Error(err, "Invalid ScaleGroup", "namespace", item.Namespace, "name", item.Name)
+		setScaleGroupStatus(&item, nil, nil, err)
+		return ctrl.Result{}, r.Status().Update(ctx, &item)
+	}
+
+	sources, err := r.loadSources(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	statuses := r.applyTargets(ctx, sources, item.Namespace, func(obj client.Object) bool {
+		_, ok := group.keyOf(obj)
+		return ok
+	})
+
+	setScaleGroupStatus(&item, group, statuses, nil)
+	if err := r.Status().Update(ctx, &item); err != nil {
+		return ctrl.Result{}, err
+	}
+	for _, member := range item.Status.Members {
+		if member.Waiting != "" {
+			return ctrl.Result{RequeueAfter: groupPollInterval}, nil
+		}
+	}
+	return ctrl.Result{RequeueAfter: statusResyncPeriod}, nil
+}
+
+// setScaleGroupStatus records the order and member states of item. A non-nil
+// groupErr marks the group invalid.
+func setScaleGroupStatus(
+	item *autoscalev1alpha1.ScaleGroup,
+	group *scaleGroup,
+	targets []autoscalev1alpha1.TargetStatus,
+	groupErr error,
+) {
+	status := &item.Status
+	status.ObservedGeneration = item.Generation
+	status.Order = nil
+	status.Members = nil
+
+	invalid := metav1.Condition{
+		Type:               autoscalev1alpha1.ConditionInvalidGroup,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Valid",
+		ObservedGeneration: item.Generation,
+	}
+	ready := metav1.Condition{
+		Type:               autoscalev1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Reconciled",
+		ObservedGeneration: item.Generation,
+	}
+	if groupErr != nil {
+		invalid.Status = metav1.ConditionTrue
+		invalid.Reason = "InvalidDependencies"
+		invalid.Message = groupErr.Error()
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "InvalidGroup"
+		ready.Message = "members are scaled without ordering"
+	} else {
+		for _, tier := range group.tiers {
+			status.Order = append(status.Order, strings.Join(tier, ","))
+		}
+		byKey := make(map[string]autoscalev1alpha1.TargetStatus, len(targets))
+		for _, target := range targets {
+			byKey[memberKey(target.Kind, target.Name)] = target
+		}
+		for _, tier := range group.tiers {
+			for _, key := range tier {
+				member := autoscalev1alpha1.ScaleGroupMemberStatus{Name: key}
+				if target, ok := byKey[key]; ok {
+					member.Phase = target.Phase
+					member.Waiting = target.Waiting
+				}
+				status.Members = append(status.Members, member)
+			}
+		}
+	}
+	apimeta.SetStatusCondition(&status.Conditions, invalid)
+	apimeta.SetStatusCondition(&status.Conditions, ready)
+}
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+)
+
+func groupMember(kind autoscalev1alpha1.TargetKind, name string, dependsOn ...string) autoscalev1alpha1.ScaleGroupMember {
+	return autoscalev1alpha1.ScaleGroupMember{
+		TargetReference: autoscalev1alpha1.TargetReference{Kind: kind, Name: name},
+		DependsOn:       dependsOn,
+	}
+}
+
+func shopGroup() *autoscalev1alpha1.ScaleGroup {
+	return &autoscalev1alpha1.ScaleGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team-a"},
+		Spec: autoscalev1alpha1.ScaleGroupSpec{Members: []autoscalev1alpha1.ScaleGroupMember{
+			groupMember(autoscalev1alpha1.KindDeployment, "frontend", "api"),
+			groupMember(autoscalev1alpha1.KindDeployment, "api", "postgres", "redis"),
+			groupMember(autoscalev1alpha1.KindStatefulSet, "postgres"),
+			groupMember(autoscalev1alpha1.KindDeployment, "redis"),
+		}},
+	}
+}
+
+func TestNewScaleGroup(t *testing.T) {
+	group, err := newScaleGroup(shopGroup())
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Deployment/redis", "StatefulSet/postgres"}, {"Deployment/api"}, {"Deployment/frontend"}}, group.tiers)
+
+	// A Deployment and a StatefulSet may share a name, and are then told apart
+	// by Kind/name
+	group, err = newScaleGroup(&autoscalev1alpha1.ScaleGroup{Spec: autoscalev1alpha1.ScaleGroupSpec{Members: []autoscalev1alpha1.ScaleGroupMember{
+		groupMember(autoscalev1alpha1.KindStatefulSet, "redis"),
+		groupMember(autoscalev1alpha1.KindDeployment, "redis", "StatefulSet/redis"),
+		groupMember(autoscalev1alpha1.KindDeployment, "api", "Deployment/redis"),
+	}}})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"StatefulSet/redis"}, {"Deployment/redis"}, {"Deployment/api"}}, group.tiers)
+
+	tests := []struct {
+		name     string
+		members  []autoscalev1alpha1.ScaleGroupMember
+		errorMsg string
+	}{
+		{
+			"cycle",
+			[]autoscalev1alpha1.ScaleGroupMember{
+				groupMember(autoscalev1alpha1.KindDeployment, "a", "b"),
+				groupMember(autoscalev1alpha1.KindDeployment, "b", "a"),
+				groupMember(autoscalev1alpha1.KindDeployment, "c"),
+			},
+			"dependency cycle between Deployment/a, Deployment/b",
+		},
+		{
+			"unknown dependency",
+			[]autoscalev1alpha1.ScaleGroupMember{groupMember(autoscalev1alpha1.KindDeployment, "a", "db")},
+			`member "Deployment/a" depends on unknown member "db"`,
+		},
+		{
+			"duplicate",
+			[]autoscalev1alpha1.ScaleGroupMember{
+				groupMember(autoscalev1alpha1.KindDeployment, "a"),
+				groupMember(autoscalev1alpha1.KindDeployment, "a"),
+			},
+			`duplicate member "Deployment/a"`,
+		},
+		{
+			"ambiguous dependency",
+			[]autoscalev1alpha1.ScaleGroupMember{
+				groupMember(autoscalev1alpha1.KindDeployment, "a", "redis"),
+				groupMember(autoscalev1alpha1.KindDeployment, "redis"),
+				groupMember(autoscalev1alpha1.KindStatefulSet, "redis"),
+			},
+			`member "Deployment/a" depends on ambiguous member "redis", use one of Deployment/redis, StatefulSet/redis`,
+		},
+	}
+	for _, test := range tests {
+		_, err := newScaleGroup(&autoscalev1alpha1.ScaleGroup{Spec: autoscalev1alpha1.ScaleGroupSpec{Members: test.members}})
+		assert.EqualError(t, err, test.errorMsg, "unexpected result for test case: %s", test.name)
+	}
+}
+
+func TestHeldMembers(t *testing.T) {
+	group, err := newScaleGroup(shopGroup())
+	assert.NoError(t, err)
+	replicas := int32(2)
+	deployment := func(name string, ready int32, annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: name, Annotations: annotations},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{Replicas: ready, ReadyReplicas: ready},
+		}
+	}
+	statefulSet := func(name string, ready int32, annotations map[string]string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: name, Annotations: annotations},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status:     appsv1.StatefulSetStatus{Replicas: ready, ReadyReplicas: ready},
+		}
+	}
+	down := map[string]string{PreviousReplicasAnnotation: "2"}
+	sources := &scheduleSources{groups: []*scaleGroup{group}}
+	sources.nsAnnotations = map[string]map[string]string{"team-a": {
+		UptimeAnnotation:   "Mon-Fri 08:00-20:00 UTC",
+		DowntimeAnnotation: "Mon-Fri 21:00-23:00 UTC",
+	}}
+	morning := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	night := time.Date(2025, 6, 2, 22, 0, 0, 0, time.UTC)
+
+	// Waking up: postgres is starting, so api waits while redis is ready
+	postgres := statefulSet("postgres", 0, down)
+	redis := deployment("redis", 2, nil)
+	api := deployment("api", 0, down)
+	frontend := deployment("frontend", 0, down)
+	held := sources.heldMembers([]client.Object{postgres, redis, api, frontend}, morning)
+	assert.Equal(t, map[client.Object]string{
+		api:      "waiting for StatefulSet/postgres to be ready",
+		frontend: "waiting for Deployment/api to be ready",
+	}, held)
+
+	// Going to sleep: frontend is still up, api and therefore postgres and redis wait
+	postgres = statefulSet("postgres", 2, nil)
+	redis = deployment("redis", 2, nil)
+	api = deployment("api", 2, nil)
+	frontend = deployment("frontend", 2, nil)
+	held = sources.heldMembers([]client.Object{postgres, redis, api, frontend}, night)
+	assert.Equal(t, map[client.Object]string{
+		api:      "waiting for Deployment/frontend to be down",
+		postgres: "waiting for Deployment/api to be down",
+		redis:    "waiting for Deployment/api to be down",
+	}, held)
+
+	// A dependent that was already at zero, without kubescale scaling it, is
+	// down
+	zero := int32(0)
+	frontend = deployment("frontend", 0, nil)
+	frontend.Spec.Replicas = &zero
+	held = sources.heldMembers([]client.Object{postgres, redis, api, frontend}, night)
+	assert.NotContains(t, held, api)
+	assert.Equal(t, "waiting for Deployment/api to be down", held[postgres])
+
+	// So is one whose kubescale/replicas is above its current count
+	frontend = deployment("frontend", 2, map[string]string{CustomReplicaAnnotation: "3"})
+	held = sources.heldMembers([]client.Object{postgres, redis, api, frontend}, night)
+	assert.NotContains(t, held, api)
+
+	// Members of other namespaces are not part of the group
+	other := deployment("api", 0, down)
+	other.Namespace = "team-b"
+	assert.Empty(t, sources.heldMembers([]client.Object{other, deployment("frontend", 0, down)}, morning))
+}
+
+func TestWorkloadReadyUnstructured(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     map[string]interface{}
+		status   map[string]interface{}
+		expected bool
+	}{
+		{"all available", map[string]interface{}{"replicas": int64(3)}, map[string]interface{}{"availableReplicas": int64(3)}, true},
+		{"starting", map[string]interface{}{"replicas": int64(3)}, map[string]interface{}{"availableReplicas": int64(1)}, false},
+		{"replicas default to one", map[string]interface{}{}, map[string]interface{}{}, false},
+		{"one available by default", map[string]interface{}{}, map[string]interface{}{"availableReplicas": int64(1)}, true},
+		{"spec not yet observed", map[string]interface{}{"replicas": int64(1)}, map[string]interface{}{"availableReplicas": int64(1), "observedGeneration": int64(1)}, false},
+		{"spec observed", map[string]interface{}{"replicas": int64(1)}, map[string]interface{}{"availableReplicas": int64(1), "observedGeneration": int64(2)}, true},
+	}
+
+	for _, test := range tests {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": test.spec, "status": test.status}}
+		obj.SetGeneration(2)
+		assert.Equal(t, test.expected, workloadReady(obj), "unexpected result for test case: %s", test.name)
+	}
+}
@@ -24,49 +24,48 @@ import (
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-func (r *ScalerReconciler) handleCronJob(ctx context.Context, nsAnnotations map[string]string, cj *batchv1.CronJob) {
+func (r *ScalerReconciler) handleCronJob(ctx context.Context, target *scheduleTarget, cj *batchv1.CronJob, now time.Time) error {
 	log := ctrllog.FromContext(ctx)
-	annotations := MergeAnnotations(nsAnnotations, cj.Annotations)
+	annotations := MergeAnnotations(target.defaults, cj.Annotations)
 	if annotations == nil {
-		return
+		return nil
 	}
-	if shouldSkipResource(&cj.ObjectMeta) {
+	if target.skip(&cj.ObjectMeta, now) {
 		log.Info("Skipping CronJob", "namespace", cj.Namespace, "name", cj.Name)
-		return
+		return nil
 	}
 
-	var inUptime, inDowntime bool
-
-	if val, ok := annotations[DowntimeAnnotation]; ok {
-		timerange, err := parseScalerAnnotation(val)
-		if err == nil {
-			inDowntime = timerange.isInRange(time.Time{})
-		}
+	inUptime, inDowntime := target.evaluate(annotations, now)
+	released := downUntilPassed(cj.Annotations, now)
+	if released {
+		delete(cj.Annotations, DownUntilAnnotation)
 	}
 
-	if !inDowntime {
-		if val, ok := annotations[UptimeAnnotation]; ok {
-			timerange, err := parseScalerAnnotation(val)
-			if err == nil {
-				inUptime = timerange.isInRange(time.Time{})
-			}
-		}
-	}
-
-	// Suspend if in downtime
+	// Suspend if in downtime, marking it as suspended by kubescale
 	if inDowntime && (cj.Spec.Suspend == nil || !*cj.Spec.Suspend) {
 		log.Info("Suspending CronJob", "namespace", cj.Namespace, "name", cj.Name)
+		if cj.Annotations == nil {
+			cj.Annotations = make(map[string]string)
+		}
+		cj.Annotations[SuspendedAnnotation] = "true"
 		s := true
 		cj.Spec.Suspend = &s
-		_ = r.Client.Update(ctx, cj)
-		return
+		return r.Client.Update(ctx, cj)
 	}
 
-	// Resume if in uptime and not in downtime
-	if !inDowntime && inUptime && (cj.Spec.Suspend != nil && *cj.Spec.Suspend) {
+	// Resume if kubescale suspended it, in uptime and not in downtime or once
+	// kubescale/down-until has passed. CronJobs suspended by hand stay
+	// suspended.
+	resume := (inUptime || released) && wasScaledDown(cj.Annotations)
+	if !inDowntime && resume && (cj.Spec.Suspend != nil && *cj.Spec.Suspend) {
 		log.Info("Resuming CronJob", "namespace", cj.Namespace, "name", cj.Name)
+		delete(cj.Annotations, SuspendedAnnotation)
 		s := false
 		cj.Spec.Suspend = &s
-		_ = r.Client.Update(ctx, cj)
+		return r.Client.Update(ctx, cj)
+	}
+	if released {
+		return r.Client.Update(ctx, cj)
 	}
+	return nil
 }
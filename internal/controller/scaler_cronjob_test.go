@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHandleCronJob(t *testing.T) {
+	clamp := newClampPolicy(t)
+	during, outside := duringRequiredDowntime, outsideRequiredDowntime
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		policy      *scalePolicy
+		now         time.Time
+		suspended   bool
+		expected    bool
+		marked      string
+	}{
+		{"suspended in downtime", map[string]string{DowntimeAnnotation: "00:00-00:00"}, nil, outside, false, true, "true"},
+		{"resumed in uptime", map[string]string{UptimeAnnotation: "00:00-00:00", SuspendedAnnotation: "true"}, nil, outside, true, false, ""},
+		{"suspended by hand in uptime", map[string]string{UptimeAnnotation: "00:00-00:00"}, nil, outside, true, true, ""},
+		{"suspended in the required downtime", map[string]string{}, clamp, during, false, true, "true"},
+		{"kept suspended in the required downtime", map[string]string{SuspendedAnnotation: "true"}, clamp, during, true, true, "true"},
+		{"suspended by hand under a clamp", map[string]string{}, clamp, outside, true, true, ""},
+		{"suspended by kubescale under a clamp", map[string]string{SuspendedAnnotation: "true"}, clamp, outside, true, false, ""},
+		{"resumed once down-until passed", map[string]string{DownUntilAnnotation: "2020-01-01T00:00:00Z", SuspendedAnnotation: "true"}, nil, outside, true, false, ""},
+		{"suspended by hand after down-until passed", map[string]string{DownUntilAnnotation: "2020-01-01T00:00:00Z"}, nil, outside, true, true, ""},
+	}
+
+	for _, test := range tests {
+		cj := &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "team-a", Annotations: test.annotations},
+			Spec:       batchv1.CronJobSpec{Suspend: &test.suspended},
+		}
+		r := &ScalerReconciler{Client: fake.NewClientBuilder().WithObjects(cj).Build()}
+		target := &scheduleTarget{sources: &scheduleSources{}, policy: test.policy}
+		assert.NoError(t, r.handleCronJob(context.Background(), target, cj, test.now), "did not expect an error for test case: %s", test.name)
+
+		var updated batchv1.CronJob
+		assert.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(cj), &updated))
+		assert.Equal(t, test.expected, *updated.Spec.Suspend, "unexpected suspend for test case: %s", test.name)
+		assert.Equal(t, test.marked, updated.Annotations[SuspendedAnnotation], "unexpected suspended marker for test case: %s", test.name)
+		assert.NotContains(t, updated.Annotations, PreviousReplicasAnnotation, "previous-replicas set for test case: %s", test.name)
+		assert.NotContains(t, updated.Annotations, DownUntilAnnotation, "passed down-until kept for test case: %s", test.name)
+	}
+}
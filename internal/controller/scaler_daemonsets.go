@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -27,71 +28,62 @@ import (
 
 func (r *ScalerReconciler) handleDaemonSets(
 	ctx context.Context,
-	nsAnnotations map[string]string,
+	target *scheduleTarget,
 	ds *appsv1.DaemonSet,
+	now time.Time,
 	updateFunc func(int32) error,
-) {
+) error {
 	log := ctrllog.FromContext(ctx)
 	meta := &ds.ObjectMeta
-	annotations := MergeAnnotations(nsAnnotations, meta.Annotations)
+	annotations := MergeAnnotations(target.defaults, meta.Annotations)
 	if annotations == nil {
 		log.Info("No annotations found for resource", "namespace", meta.Namespace, "name", meta.Name)
-		return
+		return nil
 	}
-	if shouldSkipResource(meta) {
+	if target.skip(meta, now) {
 		log.Info("Skipping resource", "namespace", meta.Namespace, "name", meta.Name)
-		return
+		return nil
 	}
 
-	var inUptime, inDowntime bool
-
-	// Handle downtime first (it takes priority)
-	if val, ok := annotations[DowntimeAnnotation]; ok {
-		timerange, err := parseScalerAnnotation(val)
-		if err == nil {
-			inDowntime = timerange.isInRange(time.Time{})
-		}
-	}
-
-	if !inDowntime {
-		if val, ok := annotations[UptimeAnnotation]; ok {
-			timerange, err := parseScalerAnnotation(val)
-			if err == nil {
-				inUptime = timerange.isInRange(time.Time{})
-			}
-		}
+	inUptime, inDowntime := target.evaluate(annotations, now)
+	released := downUntilPassed(meta.Annotations, now)
+	if released {
+		delete(meta.Annotations, DownUntilAnnotation)
 	}
 
 	// scale to 0 if in downtime
-	if inDowntime {
-		// Save current replica count
+	_, suspended := meta.Annotations[PreviousReplicasAnnotation]
+	if inDowntime && !suspended {
+		// Save current node selector
 		nodeSelectorJSON, err := json.Marshal(ds.Spec.Template.Spec.NodeSelector)
 		if err != nil {
-			log.Error(err, "Failed to serialize NodeSelector", "namespace", meta.Namespace, "name", meta.Name)
-			return
+			return fmt.Errorf("failed to serialize NodeSelector: %w", err)
+		}
+		if meta.Annotations == nil {
+			meta.Annotations = make(map[string]string)
 		}
-		ds.ObjectMeta.Annotations[PreviousReplicasAnnotation] = string(nodeSelectorJSON)
+		meta.Annotations[PreviousReplicasAnnotation] = string(nodeSelectorJSON)
 		log.Info("Force NodeSelector", "namespace", meta.Namespace, "name", meta.Name)
 		ds.Spec.Template.Spec.NodeSelector = map[string]string{
 			"kubescale-suspend-daemonset": "true",
 		}
-		_ = r.Client.Update(ctx, ds)
-		return
+		return r.Client.Update(ctx, ds)
 	}
 
-	// restore if not in downtime and in uptime
-	if !inDowntime && inUptime {
+	// restore if not in downtime and in uptime, or once kubescale/down-until
+	// has passed
+	if !inDowntime && (inUptime || released) && suspended {
 		var nodeSelector map[string]string
-		if val, ok := annotations[PreviousReplicasAnnotation]; ok {
-			if err := json.Unmarshal([]byte(val), &nodeSelector); err != nil {
-				log.Error(err, "Failed to deserialize NodeSelector", "namespace", meta.Namespace, "name", meta.Name)
-				return
-			}
+		if err := json.Unmarshal([]byte(meta.Annotations[PreviousReplicasAnnotation]), &nodeSelector); err != nil {
+			return fmt.Errorf("failed to deserialize NodeSelector: %w", err)
 		}
 		ds.Spec.Template.Spec.NodeSelector = nodeSelector
-		delete(ds.ObjectMeta.Annotations, PreviousReplicasAnnotation)
+		delete(meta.Annotations, PreviousReplicasAnnotation)
 		log.Info("Restoring NodeSelector", "namespace", meta.Namespace, "name", meta.Name)
-		_ = r.Client.Update(ctx, ds)
-		return
+		return r.Client.Update(ctx, ds)
+	}
+	if released {
+		return r.Client.Update(ctx, ds)
 	}
+	return nil
 }
@@ -22,16 +22,13 @@ package controller
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
-	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 var PrometheusGVR = schema.GroupVersionResource{
@@ -40,92 +37,36 @@ var PrometheusGVR = schema.GroupVersionResource{
 	Resource: "prometheuses",
 }
 
-func (r *ScalerReconciler) handlePrometheus(ctx context.Context, nsAnnotations map[string]string, p *unstructured.Unstructured) error {
-	log := ctrllog.FromContext(ctx)
-	annotations := MergeAnnotations(nsAnnotations, p.GetAnnotations())
-	if annotations == nil {
-		return nil
-	}
-
-	objectMeta := &metav1.ObjectMeta{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(p.Object, objectMeta); err != nil {
-		return fmt.Errorf("failed to convert metadata to ObjectMeta: %v", err)
-	}
-
-	if shouldSkipResource(objectMeta) {
-		log.Info("Skipping Prometheus", "namespace", p.GetNamespace(), "name", p.GetName())
-		return nil
-	}
+// handlePrometheus scales a Prometheus like any replicated resource, through
+// its spec.replicas.
+func (r *ScalerReconciler) handlePrometheus(ctx context.Context, target *scheduleTarget, p *unstructured.Unstructured, now time.Time) error {
+	objectMeta := &metav1.ObjectMeta{Namespace: p.GetNamespace(), Name: p.GetName(), Annotations: p.GetAnnotations()}
 
-	var inUptime, inDowntime bool
-
-	if val, ok := annotations[DowntimeAnnotation]; ok {
-		timerange, err := parseScalerAnnotation(val)
-		if err == nil {
-			inDowntime = timerange.isInRange(time.Time{})
-		}
-	}
-
-	if !inDowntime {
-		if val, ok := annotations[UptimeAnnotation]; ok {
-			timerange, err := parseScalerAnnotation(val)
-			if err == nil {
-				inUptime = timerange.isInRange(time.Time{})
-			}
-		}
-	}
-	// log.Info("Prometheus Annotations", "namespace", p.GetNamespace(), "name", p.GetName(), "annotations", annotations)
-	// fmt.Println("inUptime:", inUptime)
-	// fmt.Println("inDowntime:", inDowntime)
-	currentReplicas, _, err := unstructured.NestedInt64(p.Object, "spec", "replicas")
+	// The operator runs a single replica when spec.replicas is unset
+	replicas := int32(1)
+	current, found, err := unstructured.NestedInt64(p.Object, "spec", "replicas")
 	if err != nil {
-		// log.Error("failed to get replicas")
 		return fmt.Errorf("failed to get replicas: %v", err)
 	}
-	dynamicClient, err := dynamic.NewForConfig(config.GetConfigOrDie())
-	if err != nil {
-		return fmt.Errorf("failed to create dynamic client: %w", err)
+	if found {
+		replicas = int32(current)
 	}
 
-	// Suspend if in downtime
-	if inDowntime && currentReplicas > 0 {
-		log.Info("Suspending Prometheus", "namespace", p.GetNamespace(), "name", p.GetName())
-
-		err = unstructured.SetNestedField(p.Object, int64(0), "spec", "replicas")
-		if err != nil {
+	return r.handleReplicatedResource(ctx, target, objectMeta, &replicas, now, func(newReplicas int32) error {
+		if err := unstructured.SetNestedField(p.Object, int64(newReplicas), "spec", "replicas"); err != nil {
 			return fmt.Errorf("failed to set replicas: %v", err)
 		}
-		// Add annotation to store the current replicas
-		annotations[PreviousReplicasAnnotation] = strconv.Itoa(int(currentReplicas))
-		p.SetAnnotations(annotations)
-		// / Update the Prometheus object
-		_, err = dynamicClient.Resource(PrometheusGVR).Namespace(p.GetNamespace()).Update(ctx, p, metav1.UpdateOptions{})
+		// Only the object's own annotations are written back, not the
+		// inherited ones.
+		p.SetAnnotations(objectMeta.Annotations)
+		dynamicClient, err := dynamic.NewForConfig(config.GetConfigOrDie())
 		if err != nil {
-			return fmt.Errorf("failed to update Prometheus: %v", err)
-		}
-	}
-
-	// Resume if in uptime and not in downtime
-	if !inDowntime && inUptime && currentReplicas == 0 {
-		log.Info("Resuming Prometheus", "namespace", p.GetNamespace(), "name", p.GetName())
-		restore := int64(1)
-		if val, ok := annotations[PreviousReplicasAnnotation]; ok {
-			if prev, err := strconv.Atoi(val); err == nil && prev > 0 {
-				restore = int64(prev)
-			}
+			return fmt.Errorf("failed to create dynamic client: %w", err)
 		}
-		delete(annotations, PreviousReplicasAnnotation)
-		p.SetAnnotations(annotations)
-		err = unstructured.SetNestedField(p.Object, restore, "spec", "replicas")
-		if err != nil {
-			return fmt.Errorf("failed to set replicas: %v", err)
-		}
-		// / Update the Prometheus object
 		_, err = dynamicClient.Resource(PrometheusGVR).Namespace(p.GetNamespace()).Update(ctx, p, metav1.UpdateOptions{})
 		if err != nil {
 			return fmt.Errorf("failed to update Prometheus: %v", err)
 		}
-	}
-
-	return nil
+		return nil
+	})
 }
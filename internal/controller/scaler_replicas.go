@@ -22,70 +22,81 @@ import (
 	"strconv"
 	"time"
 
-	appsv1 "k8s.io/api/apps/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// handleReplicatedResource scales a resource with a replica count to its
+// kubescale/replicas count or percentage, 0 by default, in downtime, and to
+// the count of the active profile otherwise, saving the count it had in
+// kubescale/previous-replicas. It restores that count in uptime or outside
+// every profile, or once its kubescale/down-until has passed outside
+// downtime. Schedules are evaluated at now. updateFunc writes the new count,
+// along with meta, back.
 func (r *ScalerReconciler) handleReplicatedResource(
 	ctx context.Context,
+	target *scheduleTarget,
 	meta *meta.ObjectMeta,
-	nsAnnotations map[string]string,
 	replicas *int32,
+	now time.Time,
 	updateFunc func(int32) error,
-) {
+) error {
 	log := ctrllog.FromContext(ctx)
-	annotations := MergeAnnotations(nsAnnotations, meta.Annotations)
+	annotations := MergeAnnotations(target.defaults, meta.Annotations)
 	if annotations == nil {
 		log.Info("No annotations found for resource", "namespace", meta.Namespace, "name", meta.Name)
-		return
+		return nil
 	}
-	if shouldSkipResource(meta) {
+	if target.skip(meta, now) {
 		log.Info("Skipping resource", "namespace", meta.Namespace, "name", meta.Name)
-		return
+		return nil
 	}
 
-	var inUptime, inDowntime bool
-
-	// Handle downtime first (it takes priority)
-	if val, ok := annotations[DowntimeAnnotation]; ok {
-		timerange, err := parseScalerAnnotation(val)
-		if err == nil {
-			inDowntime = timerange.isInRange(time.Time{})
-		}
+	inUptime, inDowntime := target.evaluate(annotations, now)
+	_, scaledDown := meta.Annotations[PreviousReplicasAnnotation]
+	released := downUntilPassed(meta.Annotations, now)
+	if released {
+		delete(meta.Annotations, DownUntilAnnotation)
 	}
 
-	if !inDowntime {
-		if val, ok := annotations[UptimeAnnotation]; ok {
-			timerange, err := parseScalerAnnotation(val)
-			if err == nil {
-				inUptime = timerange.isInRange(time.Time{})
+	// scale to the active profile, or in downtime to the downtime replica
+	// count, which never goes above the count the resource had before
+	var desired int32
+	profile, scale := target.profile(annotations, now)
+	if scale {
+		desired = profile.resolve(annotations, replicasBefore(annotations, *replicas))
+	} else if inDowntime {
+		desired, scale = downtimeCount(annotations, *replicas), true
+	}
+	if scale && *replicas != desired {
+		// Save current replica count, unless an earlier pass already did
+		if !scaledDown {
+			if meta.Annotations == nil {
+				meta.Annotations = make(map[string]string)
 			}
+			meta.Annotations[PreviousReplicasAnnotation] = fmt.Sprintf("%d", *replicas)
 		}
+		log.Info("Scaling resource", "namespace", meta.Namespace, "name", meta.Name, "replicas", desired)
+		return updateFunc(desired)
 	}
 
-	// scale to 0 if in downtime
-	if inDowntime && *replicas != 0 {
-		// Save current replica count
-		meta.Annotations[PreviousReplicasAnnotation] = fmt.Sprintf("%d", *replicas)
-		log.Info("Scaling down resource", "namespace", meta.Namespace, "name", meta.Name)
-		_ = updateFunc(0)
-		_ = r.Client.Update(ctx, &appsv1.Deployment{
-			ObjectMeta: *meta,
-		})
-		return
-	}
-
-	// restore if not in downtime and in uptime
-	if !inDowntime && inUptime && *replicas == 0 {
+	// restore what kubescale scaled down if not in downtime and in uptime,
+	// outside every profile or once kubescale/down-until has passed
+	_, profiled := annotations[ProfilesAnnotation]
+	if !scale && !inDowntime && (inUptime || profiled || released) && scaledDown {
 		restore := int32(1)
 		if val, ok := annotations[PreviousReplicasAnnotation]; ok {
-			if prev, err := strconv.Atoi(val); err == nil && prev > 0 {
+			if prev, err := strconv.Atoi(val); err == nil && prev >= 0 {
 				restore = int32(prev)
 			}
 		}
 		delete(meta.Annotations, PreviousReplicasAnnotation)
 		log.Info("Restoring resource", "namespace", meta.Namespace, "name", meta.Name)
-		_ = updateFunc(restore)
+		return updateFunc(restore)
+	}
+	if released {
+		// Only drop the kubescale/down-until that has passed
+		return updateFunc(*replicas)
 	}
+	return nil
 }
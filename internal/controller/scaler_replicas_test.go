@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHandleReplicatedResource(t *testing.T) {
+	// A window from midnight to midnight is always active
+	downtime := map[string]string{DowntimeAnnotation: "00:00-00:00"}
+	uptime := map[string]string{UptimeAnnotation: "00:00-00:00"}
+	profiled := map[string]string{ProfilesAnnotation: "2: 00:00-00:00"}
+	unprofiled := map[string]string{ProfilesAnnotation: "2: 2020-01-01 00:00 - 2020-01-02 00:00"}
+	passed := map[string]string{DownUntilAnnotation: "2020-01-01T00:00:00Z"}
+	with := func(base map[string]string, kv ...string) map[string]string {
+		ann := MergeAnnotations(base, nil)
+		for i := 0; i < len(kv); i += 2 {
+			ann[kv[i]] = kv[i+1]
+		}
+		return ann
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		replicas    int32
+		expected    int32 // -1 when left alone
+		previous    string
+	}{
+		{"down to zero", downtime, 4, 0, "4"},
+		{"down to kubescale/replicas", with(downtime, CustomReplicaAnnotation, "1"), 4, 1, "4"},
+		{"already at kubescale/replicas", with(downtime, CustomReplicaAnnotation, "1", PreviousReplicasAnnotation, "4"), 1, -1, "4"},
+		{"kubescale/replicas raised in downtime", with(downtime, CustomReplicaAnnotation, "2", PreviousReplicasAnnotation, "4"), 1, 2, "4"},
+		{"never above the count before", with(downtime, CustomReplicaAnnotation, "3"), 2, -1, ""},
+		{"restored from kubescale/replicas", with(uptime, CustomReplicaAnnotation, "1", PreviousReplicasAnnotation, "4"), 1, 4, ""},
+		{"restored from zero", with(uptime, PreviousReplicasAnnotation, "4"), 0, 4, ""},
+		{"zero not set by kubescale", uptime, 0, -1, ""},
+		{"running in uptime", uptime, 3, -1, ""},
+		{"to the active profile", profiled, 6, 2, "6"},
+		{"profile above the count before", profiled, 1, 2, "1"},
+		{"already at the active profile", with(profiled, PreviousReplicasAnnotation, "6"), 2, -1, "6"},
+		{"downtime beats profiles", with(profiled, DowntimeAnnotation, "00:00-00:00"), 6, 0, "6"},
+		{"restored outside every profile", with(unprofiled, PreviousReplicasAnnotation, "6"), 2, 6, ""},
+		{"outside every profile", unprofiled, 6, -1, ""},
+		{"zero outside every profile", unprofiled, 0, -1, ""},
+		{"restored once down-until passed", with(passed, PreviousReplicasAnnotation, "4"), 0, 4, ""},
+		{"down-until passed in downtime", with(passed, DowntimeAnnotation, "00:00-00:00", PreviousReplicasAnnotation, "4"), 0, 0, "4"},
+		{"down-until passed on a resource left alone", passed, 3, 3, ""},
+		{"down to a percentage", with(downtime, CustomReplicaAnnotation, "25%"), 4, 1, "4"},
+		{"percentage rounded up", with(downtime, CustomReplicaAnnotation, "25%"), 5, 2, "5"},
+		{"percentage rounded down", with(downtime, CustomReplicaAnnotation, "25%", ReplicaRoundingAnnotation, "down"), 5, 1, "5"},
+		{"percentage raised to min-replicas", with(downtime, CustomReplicaAnnotation, "10%", MinReplicasAnnotation, "2"), 5, 2, "5"},
+		{"percentage never compounds", with(downtime, CustomReplicaAnnotation, "25%", PreviousReplicasAnnotation, "8"), 2, -1, "8"},
+		{"percentage profile", with(unprofiled, ProfilesAnnotation, "50%: 00:00-00:00"), 6, 3, "6"},
+		{"profile raises zero", with(unprofiled, ProfilesAnnotation, "3: Mon-Sun 00:00-00:00"), 0, 3, "0"},
+		{"back to zero once the profile ends", with(unprofiled, PreviousReplicasAnnotation, "0"), 3, 0, ""},
+		{"unparsable previous replicas", with(uptime, PreviousReplicasAnnotation, "many"), 0, 1, ""},
+	}
+
+	now := time.Date(2026, 5, 13, 12, 0, 0, 0, time.UTC)
+	r := &ScalerReconciler{}
+	for _, test := range tests {
+		target := &scheduleTarget{sources: &scheduleSources{}}
+		meta := &metav1.ObjectMeta{Name: "api", Annotations: MergeAnnotations(test.annotations, nil)}
+		replicas := test.replicas
+		updated := int32(-1)
+		err := r.handleReplicatedResource(context.Background(), target, meta, &replicas, now, func(n int32) error {
+			updated = n
+			return nil
+		})
+		assert.NoError(t, err, "did not expect an error for test case: %s", test.name)
+		assert.Equal(t, test.expected, updated, "unexpected replicas for test case: %s", test.name)
+		assert.Equal(t, test.previous, meta.Annotations[PreviousReplicasAnnotation], "unexpected previous replicas for test case: %s", test.name)
+		assert.NotContains(t, meta.Annotations, DownUntilAnnotation, "passed down-until kept for test case: %s", test.name)
+	}
+}
+
+func TestHandleReplicatedResourceClamp(t *testing.T) {
+	policy := newClampPolicy(t)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		replicas    int32
+		now         time.Time
+		expected    int32 // -1 when left alone
+	}{
+		{"running in the required downtime", map[string]string{}, 3, duringRequiredDowntime, 0},
+		{"kept down in the required downtime", map[string]string{PreviousReplicasAnnotation: "3"}, 0, duringRequiredDowntime, -1},
+		{"never scaled by kubescale", map[string]string{"deployment.kubernetes.io/revision": "3"}, 0, outsideRequiredDowntime, -1},
+		{"scaled down by kubescale", map[string]string{PreviousReplicasAnnotation: "3"}, 0, outsideRequiredDowntime, 3},
+	}
+
+	r := &ScalerReconciler{}
+	for _, test := range tests {
+		target := &scheduleTarget{sources: &scheduleSources{}, policy: policy}
+		meta := &metav1.ObjectMeta{Name: "api", Annotations: MergeAnnotations(nil, test.annotations)}
+		replicas := test.replicas
+		updated := int32(-1)
+		err := r.handleReplicatedResource(context.Background(), target, meta, &replicas, test.now, func(n int32) error {
+			updated = n
+			return nil
+		})
+		assert.NoError(t, err, "did not expect an error for test case: %s", test.name)
+		assert.Equal(t, test.expected, updated, "unexpected replicas for test case: %s", test.name)
+	}
+}
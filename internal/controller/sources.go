@@ -0,0 +1,538 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+	"github.com/cicd-toolkit/kubescale/internal/schedule"
+)
+
+// scheduleSources holds the schedule inputs shared by every workload during a
+// single evaluation pass.
+type scheduleSources struct {
+	nsAnnotations  map[string]map[string]string
+	nsLabels       map[string]map[string]string
+	scalers        []autoscalev1alpha1.Scaler
+	clusterScalers []autoscalev1alpha1.ClusterScaler
+	calendars      map[string]*calendar
+	overrides      []autoscalev1alpha1.ScaleOverride
+	policies       []*scalePolicy
+	groups         []*scaleGroup
+	presets        *PresetRegistry
+	// location is the cluster default timezone of windows without one.
+	location *time.Location
+}
+
+// scheduleTarget is what the schedule sources resolve for one workload.
+type scheduleTarget struct {
+	sources *scheduleSources
+	// defaults are the kubescale annotations the workload inherits.
+	defaults map[string]string
+	// override is the state forced by an active ScaleOverride, if any.
+	override autoscalev1alpha1.OverrideState
+	// policy is the ScalePolicy of the workload namespace, if any.
+	policy *scalePolicy
+	// location is the timezone of windows without one.
+	location *time.Location
+}
+
+func (r *ScalerReconciler) loadSources(ctx context.Context) (*scheduleSources, error) {
+	log := ctrllog.FromContext(ctx)
+	sources := &scheduleSources{
+		nsAnnotations: make(map[string]map[string]string),
+		nsLabels:      make(map[string]map[string]string),
+		calendars:     make(map[string]*calendar),
+		presets:       r.Presets,
+		location:      r.Location,
+	}
+
+	// Fetch namespace annotations
+	var nsList corev1.NamespaceList
+	if err := r.Client.List(ctx, &nsList); err == nil { // List all namespaces
+		for _, ns := range nsList.Items {
+			if nsAnn := ns.GetAnnotations(); nsAnn != nil {
+				sources.nsAnnotations[ns.Name] = nsAnn
+			}
+			sources.nsLabels[ns.Name] = ns.GetLabels()
+		}
+	} else {
+		log.Error(err, "Error listing namespaces")
+	}
+
+	var scalerList autoscalev1alpha1.ScalerList
+	if err := r.Client.List(ctx, &scalerList); err != nil {
+		return nil, fmt.Errorf("failed to list scalers: %w", err)
+	}
+	sources.scalers = scalerList.Items
+	// The first Scaler by name wins when several select the same workload
+	sort.Slice(sources.scalers, func(i, j int) bool {
+		return sources.scalers[i].Name < sources.scalers[j].Name
+	})
+
+	var clusterScalerList autoscalev1alpha1.ClusterScalerList
+	if err := r.Client.List(ctx, &clusterScalerList); err != nil {
+		return nil, fmt.Errorf("failed to list clusterscalers: %w", err)
+	}
+	sources.clusterScalers = clusterScalerList.Items
+	sort.Slice(sources.clusterScalers, func(i, j int) bool {
+		return sources.clusterScalers[i].Name < sources.clusterScalers[j].Name
+	})
+
+	var calendarList autoscalev1alpha1.ScaleCalendarList
+	if err := r.Client.List(ctx, &calendarList); err != nil {
+		return nil, fmt.Errorf("failed to list scalecalendars: %w", err)
+	}
+	for _, item := range calendarList.Items {
+		cal, err := newCalendar(&item.Spec, r.Location, r.importReader(ctx))
+		if err != nil {
+			log.Error(err, "Invalid ScaleCalendar", "name", item.Name)
+			continue
+		}
+		sources.calendars[item.Name] = cal
+	}
+
+	var policyList autoscalev1alpha1.ScalePolicyList
+	if err := r.Client.List(ctx, &policyList); err != nil {
+		return nil, fmt.Errorf("failed to list scalepolicies: %w", err)
+	}
+	// The first ScalePolicy by name wins when several select the same namespace
+	sort.Slice(policyList.Items, func(i, j int) bool {
+		return policyList.Items[i].Name < policyList.Items[j].Name
+	})
+	for i := range policyList.Items {
+		policy, err := newScalePolicy(&policyList.Items[i], r.Presets, r.Location)
+		if err != nil {
+			log.Error(err, "Invalid ScalePolicy", "name", policyList.Items[i].Name)
+			continue
+		}
+		sources.policies = append(sources.policies, policy)
+	}
+
+	var groupList autoscalev1alpha1.ScaleGroupList
+	if err := r.Client.List(ctx, &groupList); err != nil {
+		return nil, fmt.Errorf("failed to list scalegroups: %w", err)
+	}
+	for i := range groupList.Items {
+		group, err := newScaleGroup(&groupList.Items[i])
+		if err != nil {
+			log.Error(err, "Invalid ScaleGroup", "namespace", groupList.Items[i].Namespace, "name", groupList.Items[i].Name)
+			continue
+		}
+		sources.groups = append(sources.groups, group)
+	}
+
+	var overrideList autoscalev1alpha1.ScaleOverrideList
+	if err := r.Client.List(ctx, &overrideList); err != nil {
+		return nil, fmt.Errorf("failed to list scaleoverrides: %w", err)
+	}
+	now := time.Now()
+	for _, item := range overrideList.Items {
+		if overrideExpired(&item, now) {
+			r.deleteExpiredOverride(ctx, &item)
+			continue
+		}
+		// An invalid override is reported as such in its status and forces nothing
+		if err := validateOverride(&item.Spec); err != nil {
+			log.Error(err, "Invalid ScaleOverride", "namespace", item.Namespace, "name", item.Name)
+			continue
+		}
+		sources.overrides = append(sources.overrides, item)
+	}
+	// The most recent override wins when several select the same workload
+	sort.Slice(sources.overrides, func(i, j int) bool {
+		return sources.overrides[j].CreationTimestamp.Before(&sources.overrides[i].CreationTimestamp)
+	})
+
+	return sources, nil
+}
+
+// targetFor resolves the schedule inputs of obj at now.
+func (s *scheduleSources) targetFor(obj client.Object, now time.Time) *scheduleTarget {
+	target := &scheduleTarget{
+		sources:  s,
+		defaults: s.defaultsFor(obj),
+		policy:   s.policyFor(obj.GetNamespace()),
+		location: s.locationFor(obj.GetNamespace()),
+	}
+	for i := range s.overrides {
+		if !overrideExpired(&s.overrides[i], now) && overrideSelects(&s.overrides[i], obj) {
+			target.override = s.overrides[i].Spec.State
+			break
+		}
+	}
+	return target
+}
+
+// locationFor returns the timezone of windows without one in namespace.
+func (s *scheduleSources) locationFor(namespace string) *time.Location {
+	return NamespaceLocation(s.nsAnnotations[namespace], s.location)
+}
+
+// NamespaceLocation returns the timezone of windows without one in a
+// namespace with annotations: its kubescale/timezone, or fallback when unset
+// or invalid, or UTC when fallback is nil.
+func NamespaceLocation(annotations map[string]string, fallback *time.Location) *time.Location {
+	if name, ok := annotations[TimezoneAnnotation]; ok {
+		if loc, err := time.LoadLocation(name); err == nil && name != "" {
+			return loc
+		}
+	}
+	if fallback == nil {
+		return time.UTC
+	}
+	return fallback
+}
+
+// defaultsFor returns the kubescale annotations obj inherits before its own
+// annotations are applied. From lowest to highest precedence: the first
+// ClusterScaler selecting obj, the namespace preset label, the namespace
+// annotations, the first Scaler selecting obj and the workload preset label.
+func (s *scheduleSources) defaultsFor(obj client.Object) map[string]string {
+	defaults := make(map[string]string)
+	for i := range s.clusterScalers {
+		if s.clusterScalerSelects(&s.clusterScalers[i], obj) {
+			defaults = scheduleAnnotations(&s.clusterScalers[i].Spec.ScheduleSpec)
+			break
+		}
+	}
+	defaults = MergeAnnotations(defaults, presetAnnotations(s.nsLabels[obj.GetNamespace()]))
+	defaults = MergeAnnotations(defaults, MergeAnnotations(s.nsAnnotations[obj.GetNamespace()], nil))
+	for i := range s.scalers {
+		if scalerSelects(&s.scalers[i], obj) {
+			defaults = MergeAnnotations(defaults, scheduleAnnotations(&s.scalers[i].Spec.ScheduleSpec))
+			break
+		}
+	}
+	return MergeAnnotations(defaults, presetAnnotations(obj.GetLabels()))
+}
+
+// presetAnnotations turns the kubescale.io/schedule label into the uptime
+// annotation it stands for.
+func presetAnnotations(labels map[string]string) map[string]string {
+	name, ok := labels[PresetLabel]
+	if !ok || name == "" {
+		return nil
+	}
+	return map[string]string{UptimeAnnotation: schedule.PresetPrefix + name}
+}
+
+// evaluate reports whether annotations put the target in uptime or downtime
+// at now. A clamping ScalePolicy takes priority over everything else.
+func (t *scheduleTarget) evaluate(annotations map[string]string, now time.Time) (inUptime, inDowntime bool) {
+	inUptime, inDowntime = t.evaluateSchedule(annotations, now)
+	if !t.policy.clamps() {
+		return inUptime, inDowntime
+	}
+	if t.policy.inRequiredDowntime(now) {
+		return false, true
+	}
+	// Without an uptime window nothing would restore a workload kubescale
+	// scaled down for the required downtime
+	if _, ok := annotations[UptimeAnnotation]; !ok && !inDowntime && wasScaledDown(annotations) {
+		return true, false
+	}
+	return inUptime, inDowntime
+}
+
+// wasScaledDown reports whether annotations carry the
+// kubescale/previous-replicas kubescale saves when it scales a workload down,
+// or the kubescale/suspended it sets when it suspends a CronJob, and so
+// whether restoring it is up to kubescale.
+func wasScaledDown(annotations map[string]string) bool {
+	_, saved := annotations[PreviousReplicasAnnotation]
+	_, suspended := annotations[SuspendedAnnotation]
+	return saved || suspended
+}
+
+// skip reports whether the workload described by meta is left alone, once
+// a clamping ScalePolicy has dropped the exclusions it forbids.
+func (t *scheduleTarget) skip(meta *metav1.ObjectMeta, now time.Time) bool {
+	override := t.override
+	if override == autoscalev1alpha1.OverrideExcluded && t.policy.clamps() && !t.policy.excludeAllowed() {
+		override = ""
+	}
+	constrained := &metav1.ObjectMeta{Annotations: t.policy.constrainExclusions(meta.Annotations, now)}
+	return shouldSkipResource(constrained, override, now)
+}
+
+// evaluateSchedule reports whether annotations put the target in uptime or
+// downtime at now. Downtime takes priority over uptime, the exceptions of the
+// referenced ScaleCalendar take priority over both, kubescale/down-until and
+// kubescale/up-until over the calendar, and an active ScaleOverride takes
+// priority over everything.
+func (t *scheduleTarget) evaluateSchedule(annotations map[string]string, now time.Time) (inUptime, inDowntime bool) {
+	if inUptime, inDowntime, ok := t.forced(annotations, now); ok {
+		return inUptime, inDowntime
+	}
+	uptime, downtime := t.schedules(annotations)
+	inDowntime = downtime.Active(now)
+	inUptime = !inDowntime && uptime.Active(now)
+	return inUptime, inDowntime
+}
+
+// forced reports the state the ScaleOverride, kubescale/down-until,
+// kubescale/up-until or ScaleCalendar exceptions put the target in at now,
+// and false when none of them applies and the schedules decide.
+func (t *scheduleTarget) forced(annotations map[string]string, now time.Time) (inUptime, inDowntime, ok bool) {
+	switch t.override {
+	case autoscalev1alpha1.OverrideUp:
+		return true, false, true
+	case autoscalev1alpha1.OverrideDown:
+		return false, true, true
+	}
+
+	if expiresAfter(annotations[DownUntilAnnotation], now) {
+		return false, true, true
+	}
+	if expiresAfter(annotations[UpUntilAnnotation], now) {
+		return true, false, true
+	}
+
+	if name, ok := annotations[CalendarAnnotation]; ok {
+		if cal, ok := t.sources.calendars[name]; ok {
+			switch cal.action(now) {
+			case autoscalev1alpha1.CalendarActionDown:
+				return false, true, true
+			case autoscalev1alpha1.CalendarActionUp:
+				return true, false, true
+			}
+		}
+	}
+	return false, false, false
+}
+
+// expiresAfter reports whether the RFC3339 time value is after now. An
+// invalid value never is.
+func expiresAfter(value string, now time.Time) bool {
+	until, err := time.Parse(time.RFC3339, value)
+	return err == nil && now.Before(until)
+}
+
+// downUntilPassed reports whether annotations carry a kubescale/down-until
+// that has passed at now. It stays until the workload is handled, which
+// brings back what kubescale scaled down unless the schedule says downtime.
+func downUntilPassed(annotations map[string]string, now time.Time) bool {
+	until, err := time.Parse(time.RFC3339, annotations[DownUntilAnnotation])
+	return err == nil && !now.Before(until)
+}
+
+// expiry returns when the kubescale/up-until or kubescale/down-until of
+// annotations next expires after a given time.
+func expiry(annotations map[string]string) func(time.Time) (time.Time, bool) {
+	return func(t time.Time) (time.Time, bool) {
+		var next time.Time
+		for _, key := range []string{UpUntilAnnotation, DownUntilAnnotation} {
+			until, err := time.Parse(time.RFC3339, annotations[key])
+			if err == nil && until.After(t) && (next.IsZero() || until.Before(next)) {
+				next = until
+			}
+		}
+		return next, !next.IsZero()
+	}
+}
+
+// schedules parses the uptime and downtime of annotations. A missing or
+// malformed value is nil, which is never active.
+func (t *scheduleTarget) schedules(annotations map[string]string) (uptime, downtime *schedule.Schedule) {
+	if val, ok := annotations[UptimeAnnotation]; ok {
+		uptime, _ = schedule.Parse(val, t.sources.presets, t.location)
+	}
+	if val, ok := annotations[DowntimeAnnotation]; ok {
+		downtime, _ = schedule.Parse(val, t.sources.presets, t.location)
+	}
+	return uptime, downtime
+}
+
+// scalerSelects reports whether scaler manages obj.
+func scalerSelects(scaler *autoscalev1alpha1.Scaler, obj client.Object) bool {
+	if scaler.Namespace != obj.GetNamespace() {
+		return false
+	}
+	kind := targetKind(obj)
+	for _, ex := range scaler.Spec.Exclusions {
+		if ex.Name == obj.GetName() && (ex.Kind == "" || ex.Kind == kind) {
+			return false
+		}
+	}
+	return targetSelects(scaler.Spec.Target, obj)
+}
+
+// clusterScalerSelects reports whether cs manages obj, based on the labels of
+// the namespace obj lives in.
+func (s *scheduleSources) clusterScalerSelects(cs *autoscalev1alpha1.ClusterScaler, obj client.Object) bool {
+	nsLabels, ok := s.nsLabels[obj.GetNamespace()]
+	if !ok {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&cs.Spec.NamespaceSelector)
+	if err != nil || !selector.Matches(labels.Set(nsLabels)) {
+		return false
+	}
+	return targetSelects(cs.Spec.Target, obj)
+}
+
+// clusterScalerNamespaces returns how many namespaces the namespace selector
+// of cs matches.
+func (s *scheduleSources) clusterScalerNamespaces(cs *autoscalev1alpha1.ClusterScaler) int {
+	selector, err := metav1.LabelSelectorAsSelector(&cs.Spec.NamespaceSelector)
+	if err != nil {
+		return 0
+	}
+	matched := 0
+	for _, nsLabels := range s.nsLabels {
+		if selector.Matches(labels.Set(nsLabels)) {
+			matched++
+		}
+	}
+	return matched
+}
+
+// targetSelects reports whether obj matches the kinds and label selector of
+// target.
+func targetSelects(target autoscalev1alpha1.ScalerTarget, obj client.Object) bool {
+	kind := targetKind(obj)
+	if kind == "" {
+		return false
+	}
+	if len(target.Kinds) > 0 && !slices.Contains(target.Kinds, kind) {
+		return false
+	}
+
+	if target.Selector == nil {
+		return true
+	}
+	selector, err := metav1.LabelSelectorAsSelector(target.Selector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// scheduleAnnotations expresses a Scaler or ClusterScaler schedule as the
+// equivalent kubescale annotations, so it goes through the same evaluation
+// as annotated workloads.
+func scheduleAnnotations(schedule *autoscalev1alpha1.ScheduleSpec) map[string]string {
+	ann := make(map[string]string)
+	if schedule.Uptime != "" {
+		ann[UptimeAnnotation] = schedule.Uptime
+	}
+	if schedule.Downtime != "" {
+		ann[DowntimeAnnotation] = schedule.Downtime
+	}
+	if schedule.DowntimeReplicas != nil {
+		ann[CustomReplicaAnnotation] = schedule.DowntimeReplicas.String()
+	}
+	if schedule.ReplicaRounding != "" {
+		ann[ReplicaRoundingAnnotation] = string(schedule.ReplicaRounding)
+	}
+	if schedule.MinReplicas != nil {
+		ann[MinReplicasAnnotation] = strconv.Itoa(int(*schedule.MinReplicas))
+	}
+	if len(schedule.Profiles) > 0 {
+		profiles := make([]string, len(schedule.Profiles))
+		for i, profile := range schedule.Profiles {
+			profiles[i] = fmt.Sprintf("%s: %s", profile.Replicas.String(), profile.Schedule)
+		}
+		ann[ProfilesAnnotation] = strings.Join(profiles, "\n")
+	}
+	if schedule.Calendar != "" {
+		ann[CalendarAnnotation] = schedule.Calendar
+	}
+	return ann
+}
+
+// validateSchedule checks the uptime, downtime and profiles of a Scaler or
+// ClusterScaler and the selectors that choose its targets.
+func validateSchedule(presets *PresetRegistry, spec *autoscalev1alpha1.ScheduleSpec, selectors ...*metav1.LabelSelector) error {
+	if spec.Uptime == "" && spec.Downtime == "" && len(spec.Profiles) == 0 {
+		return fmt.Errorf("one of uptime, downtime or profiles must be set")
+	}
+	if spec.Uptime != "" {
+		if _, err := schedule.Parse(spec.Uptime, presets, nil); err != nil {
+			return fmt.Errorf("invalid uptime: %w", err)
+		}
+	}
+	if spec.Downtime != "" {
+		if _, err := schedule.Parse(spec.Downtime, presets, nil); err != nil {
+			return fmt.Errorf("invalid downtime: %w", err)
+		}
+	}
+	if spec.DowntimeReplicas != nil {
+		if _, err := parseReplicaTarget(spec.DowntimeReplicas.String()); err != nil {
+			return fmt.Errorf("invalid downtimeReplicas: %w", err)
+		}
+	}
+	for i, profile := range spec.Profiles {
+		if _, err := parseReplicaTarget(profile.Replicas.String()); err != nil {
+			return fmt.Errorf("invalid profile %d: %w", i+1, err)
+		}
+		if _, err := schedule.Parse(profile.Schedule, presets, nil); err != nil {
+			return fmt.Errorf("invalid profile %d: %w", i+1, err)
+		}
+	}
+	return validateSelectors(selectors...)
+}
+
+// validateSelectors checks that every non-nil selector can be converted.
+func validateSelectors(selectors ...*metav1.LabelSelector) error {
+	for _, selector := range selectors {
+		if selector == nil {
+			continue
+		}
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+			return fmt.Errorf("invalid selector: %w", err)
+		}
+	}
+	return nil
+}
+
+// targetKind returns the kubescale kind of obj, or "" if it is not a
+// workload kubescale scales.
+func targetKind(obj client.Object) autoscalev1alpha1.TargetKind {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return autoscalev1alpha1.KindDeployment
+	case *appsv1.StatefulSet:
+		return autoscalev1alpha1.KindStatefulSet
+	case *appsv1.DaemonSet:
+		return autoscalev1alpha1.KindDaemonSet
+	case *batchv1.CronJob:
+		return autoscalev1alpha1.KindCronJob
+	case *unstructured.Unstructured:
+		if o.GetKind() == string(autoscalev1alpha1.KindPrometheus) {
+			return autoscalev1alpha1.KindPrometheus
+		}
+	}
+	return ""
+}
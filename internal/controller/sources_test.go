@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+)
+
+func TestScalerSelects(t *testing.T) {
+	scaler := &autoscalev1alpha1.Scaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "office-hours", Namespace: "team-a"},
+		Spec: autoscalev1alpha1.ScalerSpec{
+			Target: autoscalev1alpha1.ScalerTarget{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "backend"}},
+				Kinds:    []autoscalev1alpha1.TargetKind{autoscalev1alpha1.KindDeployment},
+			},
+			Exclusions: []autoscalev1alpha1.ScalerExclusion{{Name: "gateway"}},
+		},
+	}
+	objMeta := func(namespace, name string, labels map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}
+	}
+	backend := map[string]string{"tier": "backend"}
+
+	tests := []struct {
+		name     string
+		obj      client.Object
+		expected bool
+	}{
+		{"matching deployment", &appsv1.Deployment{ObjectMeta: objMeta("team-a", "api", backend)}, true},
+		{"other namespace", &appsv1.Deployment{ObjectMeta: objMeta("team-b", "api", backend)}, false},
+		{"label mismatch", &appsv1.Deployment{ObjectMeta: objMeta("team-a", "api", nil)}, false},
+		{"kind not listed", &batchv1.CronJob{ObjectMeta: objMeta("team-a", "api", backend)}, false},
+		{"excluded by name", &appsv1.Deployment{ObjectMeta: objMeta("team-a", "gateway", backend)}, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, scalerSelects(scaler, test.obj), "unexpected result for test case: %s", test.name)
+	}
+
+	// A Scaler without selector and kinds matches every workload in its namespace
+	all := &autoscalev1alpha1.Scaler{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}}
+	assert.True(t, scalerSelects(all, &batchv1.CronJob{ObjectMeta: objMeta("team-a", "job", nil)}))
+}
+
+func TestScheduleSourcesDefaultsFor(t *testing.T) {
+	replicas := intstr.FromInt32(1)
+	sources := &scheduleSources{
+		nsAnnotations: map[string]map[string]string{
+			"team-a": {
+				UptimeAnnotation:   "Mon-Fri 07:00-19:00 UTC",
+				DowntimeAnnotation: "Sat-Sun 00:00-23:59 UTC",
+				"unrelated":        "value",
+			},
+		},
+		scalers: []autoscalev1alpha1.Scaler{{
+			ObjectMeta: metav1.ObjectMeta{Name: "office-hours", Namespace: "team-a"},
+			Spec: autoscalev1alpha1.ScalerSpec{
+				ScheduleSpec: autoscalev1alpha1.ScheduleSpec{
+					Uptime:           "Mon-Fri 08:00-20:00 Europe/Paris",
+					DowntimeReplicas: &replicas,
+				},
+			},
+		}},
+	}
+
+	defaults := sources.defaultsFor(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "api"}})
+	assert.Equal(t, map[string]string{
+		UptimeAnnotation:        "Mon-Fri 08:00-20:00 Europe/Paris",
+		DowntimeAnnotation:      "Sat-Sun 00:00-23:59 UTC",
+		CustomReplicaAnnotation: "1",
+	}, defaults)
+}
+
+func TestClusterScalerPrecedence(t *testing.T) {
+	sources := &scheduleSources{
+		nsAnnotations: map[string]map[string]string{
+			"dev-a": {DowntimeAnnotation: "Sat-Sun 00:00-23:59 UTC"},
+		},
+		nsLabels: map[string]map[string]string{
+			"dev-a":  {"env": "dev"},
+			"prod-a": {"env": "prod"},
+		},
+		clusterScalers: []autoscalev1alpha1.ClusterScaler{{
+			ObjectMeta: metav1.ObjectMeta{Name: "dev-nights"},
+			Spec: autoscalev1alpha1.ClusterScalerSpec{
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+				ScheduleSpec: autoscalev1alpha1.ScheduleSpec{
+					Uptime:   "Mon-Fri 07:00-20:00 UTC",
+					Downtime: "Mon-Sun 00:00-06:00 UTC",
+				},
+			},
+		}},
+	}
+
+	dev := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "dev-a", Name: "api"}}
+	assert.Equal(t, map[string]string{
+		UptimeAnnotation:   "Mon-Fri 07:00-20:00 UTC",
+		DowntimeAnnotation: "Sat-Sun 00:00-23:59 UTC", // namespace annotation wins
+	}, sources.defaultsFor(dev))
+
+	prod := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "prod-a", Name: "api"}}
+	assert.Empty(t, sources.defaultsFor(prod))
+	assert.Equal(t, 1, sources.clusterScalerNamespaces(&sources.clusterScalers[0]))
+}
+
+func TestScheduleSourcesLocationFor(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	sources := &scheduleSources{
+		location: paris,
+		nsAnnotations: map[string]map[string]string{
+			"team-jp":  {TimezoneAnnotation: "Asia/Tokyo"},
+			"team-bad": {TimezoneAnnotation: "Mars/Olympus"},
+		},
+	}
+	assert.Equal(t, tokyo, sources.locationFor("team-jp"))
+	assert.Equal(t, paris, sources.locationFor("team-bad"))
+	assert.Equal(t, paris, sources.locationFor("team-fr"))
+	assert.Equal(t, time.UTC, (&scheduleSources{}).locationFor("team-fr"))
+
+	// Windows without a timezone follow the namespace, explicit ones do not
+	obj := func(namespace, downtime string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace, Name: "api", Annotations: map[string]string{DowntimeAnnotation: downtime},
+		}}
+	}
+	now := time.Date(2025, 6, 2, 7, 30, 0, 0, time.UTC) // 09:30 in Paris, 16:30 in Tokyo
+	tests := []struct {
+		name     string
+		obj      *appsv1.Deployment
+		expected autoscalev1alpha1.TargetPhase
+	}{
+		{"cluster default", obj("team-fr", "Mon-Fri 09:00-18:00"), autoscalev1alpha1.TargetPhaseDown},
+		{"namespace timezone", obj("team-jp", "Mon-Fri 09:00-16:00"), autoscalev1alpha1.TargetPhaseUp},
+		{"explicit timezone", obj("team-jp", "Mon-Fri 09:00-18:00 Europe/Paris"), autoscalev1alpha1.TargetPhaseDown},
+		{"dated window", obj("team-jp", "2025-06-02 16:00 - 2025-06-02 17:00"), autoscalev1alpha1.TargetPhaseDown},
+	}
+	for _, test := range tests {
+		target := sources.targetFor(test.obj, now)
+		assert.Equal(t, test.expected, target.phase(test.obj, now), "unexpected result for test case: %s", test.name)
+	}
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+	"github.com/cicd-toolkit/kubescale/internal/schedule"
+)
+
+// transitionHorizon is how far ahead nextTransition looks for a change.
+const transitionHorizon = 7 * 24 * time.Hour
+
+// phase reports the state obj is kept in once its handler has run.
+func (t *scheduleTarget) phase(obj client.Object, now time.Time) autoscalev1alpha1.TargetPhase {
+	own := obj.GetAnnotations()
+	if t.skip(&metav1.ObjectMeta{Annotations: own}, now) {
+		return autoscalev1alpha1.TargetPhaseExcluded
+	}
+	annotations := MergeAnnotations(t.defaults, own)
+	if replicas, ok := t.profile(annotations, now); ok {
+		if replicas.zero() {
+			return autoscalev1alpha1.TargetPhaseDown
+		}
+		return autoscalev1alpha1.TargetPhaseUp
+	}
+	inUptime, inDowntime := t.evaluate(annotations, now)
+	// Outside both windows the target keeps whatever state it was left in,
+	// unless it has profiles
+	scaledDown := wasScaledDown(own)
+	_, profiled := annotations[ProfilesAnnotation]
+	if inDowntime || (!inUptime && scaledDown && !profiled) {
+		return autoscalev1alpha1.TargetPhaseDown
+	}
+	return autoscalev1alpha1.TargetPhaseUp
+}
+
+// nextTransition returns when annotations next switch the target in or out
+// of uptime or downtime, or nil when nothing changes within
+// transitionHorizon. Only the instants at which one of the inputs changes
+// are evaluated.
+func (t *scheduleTarget) nextTransition(annotations map[string]string, now time.Time) *metav1.Time {
+	inUptime, inDowntime := t.evaluate(annotations, now)
+	replicas, profiled := t.profile(annotations, now)
+	uptime, downtime := t.schedules(annotations)
+	cal := t.sources.calendars[annotations[CalendarAnnotation]]
+	changes := []func(time.Time) (time.Time, bool){
+		uptime.NextTransition, downtime.NextTransition, t.policy.nextRequiredDowntime, cal.next, expiry(annotations),
+	}
+	for _, p := range t.profiles(annotations) {
+		changes = append(changes, p.schedule.NextTransition)
+	}
+
+	for at := now; ; {
+		var next time.Time
+		for _, change := range changes {
+			if c, ok := change(at); ok && (next.IsZero() || c.Before(next)) {
+				next = c
+			}
+		}
+		if next.IsZero() || next.Sub(now) > transitionHorizon {
+			return nil
+		}
+		up, down := t.evaluate(annotations, next)
+		n, ok := t.profile(annotations, next)
+		if up != inUptime || down != inDowntime || n != replicas || ok != profiled {
+			transition := metav1.NewTime(next)
+			return &transition
+		}
+		at = next
+	}
+}
+
+// scheduleError returns why the uptime, downtime or profiles in annotations
+// cannot be parsed. Such a schedule is ignored when the target is evaluated.
+func (t *scheduleTarget) scheduleError(annotations map[string]string) error {
+	for _, key := range []string{UptimeAnnotation, DowntimeAnnotation} {
+		if val, ok := annotations[key]; ok {
+			if _, err := schedule.Parse(val, t.sources.presets, t.location); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	if val, ok := annotations[ProfilesAnnotation]; ok {
+		if _, err := parseProfiles(val, t.sources.presets, t.location); err != nil {
+			return fmt.Errorf("%s: %w", ProfilesAnnotation, err)
+		}
+	}
+	return nil
+}
+
+// expiredWindows lists the dated windows of the uptime and downtime in
+// annotations that ended at or before now, each with its annotation.
+// Windows without a timezone are read in loc.
+func expiredWindows(annotations map[string]string, now time.Time, loc *time.Location) []string {
+	var expired []string
+	for _, key := range []string{UptimeAnnotation, DowntimeAnnotation} {
+		for _, entry := range schedule.Expired(annotations[key], now, loc) {
+			expired = append(expired, fmt.Sprintf("%s %q", key, entry))
+		}
+	}
+	return expired
+}
+
+// targetStatus builds the status entry of obj after its handler returned err,
+// or after it was held back for the reason waiting.
+func targetStatus(target *scheduleTarget, obj client.Object, err error, waiting string, now time.Time) autoscalev1alpha1.TargetStatus {
+	status := autoscalev1alpha1.TargetStatus{
+		Kind:    targetKind(obj),
+		Name:    obj.GetName(),
+		Waiting: waiting,
+	}
+	if err == nil {
+		err = target.scheduleError(MergeAnnotations(target.defaults, obj.GetAnnotations()))
+	}
+	if err != nil {
+		status.Phase = autoscalev1alpha1.TargetPhaseError
+		status.Message = err.Error()
+	} else {
+		status.Phase = target.phase(obj, now)
+		if replicas, ok := target.profile(MergeAnnotations(target.defaults, obj.GetAnnotations()), now); ok {
+			status.Replicas = replicas.intOrString()
+		}
+		// Windows inherited from the Scaler are reported on the Scaler itself
+		if expired := expiredWindows(obj.GetAnnotations(), now, target.location); len(expired) > 0 {
+			status.Message = "expired windows can be removed: " + strings.Join(expired, ", ")
+		}
+	}
+	return status
+}
+
+// setScalerStatus records the targets of scaler and its conditions. A non-nil
+// scheduleErr marks the schedule invalid, and dated windows of the spec that
+// have ended, read in loc without a timezone, are reported as expired.
+// Transition times are carried over from the previous status for targets
+// whose phase is unchanged.
+func setScalerStatus(
+	scaler *autoscalev1alpha1.Scaler,
+	targets []autoscalev1alpha1.TargetStatus,
+	next *metav1.Time,
+	scheduleErr error,
+	loc *time.Location,
+	now time.Time,
+) {
+	status := &scaler.Status
+	previous := make(map[string]autoscalev1alpha1.TargetStatus, len(status.Targets))
+	for _, target := range status.Targets {
+		previous[string(target.Kind)+"/"+target.Name] = target
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Kind != targets[j].Kind {
+			return targets[i].Kind < targets[j].Kind
+		}
+		return targets[i].Name < targets[j].Name
+	})
+
+	for i := range targets {
+		target := &targets[i]
+		prev, ok := previous[string(target.Kind)+"/"+target.Name]
+		if ok && prev.Phase == target.Phase {
+			target.LastTransitionTime = prev.LastTransitionTime
+			continue
+		}
+		transition := metav1.NewTime(now)
+		target.LastTransitionTime = &transition
+		if ok {
+			// Only a change of phase counts as a transition of the Scaler
+			status.LastTransitionTime = &transition
+		}
+	}
+
+	status.ObservedGeneration = scaler.Generation
+	status.MatchedTargets = int32(len(targets))
+	status.Targets = targets
+	status.NextTransitionTime = next
+
+	invalid, ready := scheduleConditions(targets, scheduleErr, scaler.Generation)
+	expired := metav1.Condition{
+		Type:               autoscalev1alpha1.ConditionExpiredWindows,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoneExpired",
+		ObservedGeneration: scaler.Generation,
+	}
+	if windows := expiredWindows(scheduleAnnotations(&scaler.Spec.ScheduleSpec), now, loc); len(windows) > 0 {
+		expired.Status = metav1.ConditionTrue
+		expired.Reason = "WindowsEnded"
+		expired.Message = "expired windows can be removed: " + strings.Join(windows, ", ")
+	}
+	apimeta.SetStatusCondition(&status.Conditions, invalid)
+	apimeta.SetStatusCondition(&status.Conditions, ready)
+	apimeta.SetStatusCondition(&status.Conditions, expired)
+}
+
+// setClusterScalerStatus records how many namespaces and targets cs matches
+// and its conditions. A non-nil scheduleErr marks the schedule invalid.
+func setClusterScalerStatus(
+	cs *autoscalev1alpha1.ClusterScaler,
+	namespaces int,
+	targets []autoscalev1alpha1.TargetStatus,
+	scheduleErr error,
+) {
+	status := &cs.Status
+	status.ObservedGeneration = cs.Generation
+	status.MatchedNamespaces = int32(namespaces)
+	status.MatchedTargets = int32(len(targets))
+	invalid, ready := scheduleConditions(targets, scheduleErr, cs.Generation)
+	apimeta.SetStatusCondition(&status.Conditions, invalid)
+	apimeta.SetStatusCondition(&status.Conditions, ready)
+}
+
+// scheduleConditions returns the InvalidSchedule and Ready conditions of a
+// schedule that failed to parse with scheduleErr, or else was applied to
+// targets.
+func scheduleConditions(
+	targets []autoscalev1alpha1.TargetStatus,
+	scheduleErr error,
+	generation int64,
+) (invalid, ready metav1.Condition) {
+	failed := 0
+	for _, target := range targets {
+		if target.Phase == autoscalev1alpha1.TargetPhaseError {
+			failed++
+		}
+	}
+	invalid = metav1.Condition{
+		Type:               autoscalev1alpha1.ConditionInvalidSchedule,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Valid",
+		ObservedGeneration: generation,
+	}
+	ready = metav1.Condition{
+		Type:               autoscalev1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Reconciled",
+		Message:            fmt.Sprintf("%d targets reconciled", len(targets)),
+		ObservedGeneration: generation,
+	}
+	switch {
+	case scheduleErr != nil:
+		invalid.Status = metav1.ConditionTrue
+		invalid.Reason = "ParseError"
+		invalid.Message = scheduleErr.Error()
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "InvalidSchedule"
+		ready.Message = "the schedule cannot be parsed"
+	case failed > 0:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "TargetErrors"
+		ready.Message = fmt.Sprintf("%d of %d targets failed", failed, len(targets))
+	}
+	return invalid, ready
+}
+
+// setScaleCalendarStatus records the state cal forces at now and when that
+// next changes, or the error that kept the calendar from being parsed.
+func setScaleCalendarStatus(item *autoscalev1alpha1.ScaleCalendar, cal *calendar, calErr error, now time.Time) {
+	status := &item.Status
+	status.ObservedGeneration = item.Generation
+	status.Action = ""
+	status.NextTransitionTime = nil
+	ready := metav1.Condition{
+		Type:               autoscalev1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Parsed",
+		ObservedGeneration: item.Generation,
+	}
+	if calErr != nil {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "InvalidCalendar"
+		ready.Message = calErr.Error()
+	} else {
+		status.Action = cal.action(now)
+		if at, ok := cal.nextChange(now); ok {
+			next := metav1.NewTime(at)
+			status.NextTransitionTime = &next
+		}
+	}
+	apimeta.SetStatusCondition(&status.Conditions, ready)
+}
+
+// setScaleOverrideStatus records the phase of override and how many targets
+// it applies to. A non-nil specErr explains an Invalid phase.
+func setScaleOverrideStatus(
+	override *autoscalev1alpha1.ScaleOverride,
+	phase autoscalev1alpha1.OverridePhase,
+	targets []autoscalev1alpha1.TargetStatus,
+	specErr error,
+) {
+	status := &override.Status
+	status.ObservedGeneration = override.Generation
+	status.Phase = phase
+	status.MatchedTargets = int32(len(targets))
+	status.Message = ""
+	if specErr != nil {
+		status.Message = specErr.Error()
+	}
+}
@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
+)
+
+func TestScheduleTargetPhase(t *testing.T) {
+	monday := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	night := time.Date(2025, 6, 2, 22, 0, 0, 0, time.UTC)
+	target := &scheduleTarget{
+		sources: &scheduleSources{},
+		defaults: map[string]string{
+			UptimeAnnotation:   "Mon-Fri 08:00-20:00 UTC",
+			DowntimeAnnotation: "Mon-Fri 21:00-23:00 UTC",
+		},
+	}
+	deploy := func(annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Annotations: annotations}}
+	}
+
+	tests := []struct {
+		name     string
+		obj      *appsv1.Deployment
+		override autoscalev1alpha1.OverrideState
+		now      time.Time
+		expected autoscalev1alpha1.TargetPhase
+	}{
+		{"uptime", deploy(nil), "", monday, autoscalev1alpha1.TargetPhaseUp},
+		{"downtime", deploy(nil), "", night, autoscalev1alpha1.TargetPhaseDown},
+		{"excluded", deploy(map[string]string{ExcludeAnnotation: "true"}), "", monday, autoscalev1alpha1.TargetPhaseExcluded},
+		{"override", deploy(nil), autoscalev1alpha1.OverrideExcluded, monday, autoscalev1alpha1.TargetPhaseExcluded},
+		{"up until beats downtime", deploy(map[string]string{UpUntilAnnotation: "2025-06-03T08:00:00+02:00"}), "", night, autoscalev1alpha1.TargetPhaseUp},
+		{"expired up until", deploy(map[string]string{UpUntilAnnotation: "2025-06-02T21:00:00Z"}), "", night, autoscalev1alpha1.TargetPhaseDown},
+		{"down until beats uptime", deploy(map[string]string{DownUntilAnnotation: "2025-06-05T00:00:00Z"}), "", monday, autoscalev1alpha1.TargetPhaseDown},
+		{"override beats down until", deploy(map[string]string{DownUntilAnnotation: "2025-06-05T00:00:00Z"}), autoscalev1alpha1.OverrideUp, monday, autoscalev1alpha1.TargetPhaseUp},
+		{
+			"left scaled down between windows",
+			deploy(map[string]string{PreviousReplicasAnnotation: "3"}), "",
+			time.Date(2025, 6, 2, 20, 30, 0, 0, time.UTC),
+			autoscalev1alpha1.TargetPhaseDown,
+		},
+	}
+
+	for _, test := range tests {
+		target.override = test.override
+		assert.Equal(t, test.expected, target.phase(test.obj, test.now), "unexpected result for test case: %s", test.name)
+	}
+
+	// A malformed window is reported as an error
+	target.override = ""
+	status := targetStatus(target, deploy(map[string]string{UptimeAnnotation: "Mon-Fri 08:00-12:00, Mon-Fri 13:00-1800"}), nil, "", monday)
+	assert.Equal(t, autoscalev1alpha1.TargetPhaseError, status.Phase)
+	assert.Contains(t, status.Message, `kubescale/uptime: window 2 "Mon-Fri 13:00-1800"`)
+
+	// An ended dated window is reported for cleanup
+	status = targetStatus(target, deploy(map[string]string{DowntimeAnnotation: "Sat-Sun 00:00-23:59, 2024-12-23 18:00 - 2025-01-02 07:00"}), nil, "", monday)
+	assert.NotEqual(t, autoscalev1alpha1.TargetPhaseError, status.Phase)
+	assert.Equal(t, `expired windows can be removed: kubescale/downtime "2024-12-23 18:00 - 2025-01-02 07:00"`, status.Message)
+}
+
+func TestScheduleTargetNextTransition(t *testing.T) {
+	target := &scheduleTarget{sources: &scheduleSources{}}
+	ann := map[string]string{UptimeAnnotation: "Mon-Fri 08:00-20:00 UTC"}
+
+	next := target.nextTransition(ann, time.Date(2025, 6, 2, 7, 0, 30, 0, time.UTC))
+	if assert.NotNil(t, next) {
+		assert.Equal(t, time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC), next.UTC())
+	}
+
+	// An expiry is a transition when it changes the state
+	ann[DownUntilAnnotation] = "2025-06-02T09:30:00Z"
+	next = target.nextTransition(ann, time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC))
+	if assert.NotNil(t, next) {
+		assert.Equal(t, time.Date(2025, 6, 2, 9, 30, 0, 0, time.UTC), next.UTC())
+	}
+	delete(ann, DownUntilAnnotation)
+
+	// A schedule that never changes has no next transition
+	assert.Nil(t, target.nextTransition(map[string]string{}, time.Now()))
+
+	// Calendar exceptions are transitions too
+	cal, err := newCalendar(&autoscalev1alpha1.ScaleCalendarSpec{Exceptions: []autoscalev1alpha1.CalendarException{
+		{Date: "2025-06-02", Window: "10:00-12:00", Action: autoscalev1alpha1.CalendarActionDown},
+	}}, nil, nil)
+	assert.NoError(t, err)
+	target.sources.calendars = map[string]*calendar{"maintenance": cal}
+	ann[CalendarAnnotation] = "maintenance"
+	next = target.nextTransition(ann, time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC))
+	if assert.NotNil(t, next) {
+		assert.Equal(t, time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC), next.UTC())
+	}
+}
+
+func TestSetScalerStatus(t *testing.T) {
+	first := time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)
+	later := first.Add(time.Hour)
+	scaler := &autoscalev1alpha1.Scaler{ObjectMeta: metav1.ObjectMeta{Name: "office-hours", Generation: 2}}
+
+	setScalerStatus(scaler, []autoscalev1alpha1.TargetStatus{
+		{Kind: autoscalev1alpha1.KindDeployment, Name: "web", Phase: autoscalev1alpha1.TargetPhaseDown},
+		{Kind: autoscalev1alpha1.KindDeployment, Name: "api", Phase: autoscalev1alpha1.TargetPhaseDown},
+	}, nil, nil, nil, first)
+	assert.Equal(t, int32(2), scaler.Status.MatchedTargets)
+	assert.Equal(t, "api", scaler.Status.Targets[0].Name)
+	assert.Nil(t, scaler.Status.LastTransitionTime)
+	assert.True(t, apimeta.IsStatusConditionTrue(scaler.Status.Conditions, autoscalev1alpha1.ConditionReady))
+	assert.True(t, apimeta.IsStatusConditionFalse(scaler.Status.Conditions, autoscalev1alpha1.ConditionInvalidSchedule))
+
+	setScalerStatus(scaler, []autoscalev1alpha1.TargetStatus{
+		{Kind: autoscalev1alpha1.KindDeployment, Name: "api", Phase: autoscalev1alpha1.TargetPhaseUp},
+		{Kind: autoscalev1alpha1.KindDeployment, Name: "web", Phase: autoscalev1alpha1.TargetPhaseDown},
+	}, nil, nil, nil, later)
+	assert.Equal(t, later, scaler.Status.Targets[0].LastTransitionTime.UTC())
+	assert.Equal(t, first, scaler.Status.Targets[1].LastTransitionTime.UTC())
+	assert.Equal(t, later, scaler.Status.LastTransitionTime.UTC())
+
+	setScalerStatus(scaler, []autoscalev1alpha1.TargetStatus{
+		{Kind: autoscalev1alpha1.KindDeployment, Name: "api", Phase: autoscalev1alpha1.TargetPhaseError, Message: "conflict"},
+	}, nil, nil, nil, later)
+	ready := apimeta.FindStatusCondition(scaler.Status.Conditions, autoscalev1alpha1.ConditionReady)
+	assert.Equal(t, "TargetErrors", ready.Reason)
+
+	setScalerStatus(scaler, nil, nil, errors.New("invalid uptime"), nil, later)
+	assert.True(t, apimeta.IsStatusConditionTrue(scaler.Status.Conditions, autoscalev1alpha1.ConditionInvalidSchedule))
+	assert.True(t, apimeta.IsStatusConditionFalse(scaler.Status.Conditions, autoscalev1alpha1.ConditionReady))
+	assert.Equal(t, int32(0), scaler.Status.MatchedTargets)
+	assert.True(t, apimeta.IsStatusConditionFalse(scaler.Status.Conditions, autoscalev1alpha1.ConditionExpiredWindows))
+
+	scaler.Spec.Downtime = "2024-12-23 18:00 - 2025-01-02 07:00 Europe/Berlin"
+	setScalerStatus(scaler, nil, nil, nil, nil, later)
+	expired := apimeta.FindStatusCondition(scaler.Status.Conditions, autoscalev1alpha1.ConditionExpiredWindows)
+	assert.Equal(t, metav1.ConditionTrue, expired.Status)
+	assert.Equal(t, `expired windows can be removed: kubescale/downtime "2024-12-23 18:00 - 2025-01-02 07:00 Europe/Berlin"`, expired.Message)
+}
+
+func TestSetClusterScalerStatus(t *testing.T) {
+	cs := &autoscalev1alpha1.ClusterScaler{ObjectMeta: metav1.ObjectMeta{Name: "dev-nights", Generation: 3}}
+
+	setClusterScalerStatus(cs, 2, []autoscalev1alpha1.TargetStatus{
+		{Kind: autoscalev1alpha1.KindDeployment, Name: "web", Phase: autoscalev1alpha1.TargetPhaseDown},
+		{Kind: autoscalev1alpha1.KindCronJob, Name: "report", Phase: autoscalev1alpha1.TargetPhaseError, Message: "conflict"},
+	}, nil)
+	assert.Equal(t, int64(3), cs.Status.ObservedGeneration)
+	assert.Equal(t, int32(2), cs.Status.MatchedNamespaces)
+	assert.Equal(t, int32(2), cs.Status.MatchedTargets)
+	ready := apimeta.FindStatusCondition(cs.Status.Conditions, autoscalev1alpha1.ConditionReady)
+	assert.Equal(t, "TargetErrors", ready.Reason)
+	assert.Equal(t, "1 of 2 targets failed", ready.Message)
+
+	setClusterScalerStatus(cs, 0, nil, errors.New("invalid uptime"))
+	assert.True(t, apimeta.IsStatusConditionTrue(cs.Status.Conditions, autoscalev1alpha1.ConditionInvalidSchedule))
+	assert.True(t, apimeta.IsStatusConditionFalse(cs.Status.Conditions, autoscalev1alpha1.ConditionReady))
+	assert.Equal(t, int32(0), cs.Status.MatchedTargets)
+}
+
+func TestSetScaleCalendarStatus(t *testing.T) {
+	item := &autoscalev1alpha1.ScaleCalendar{
+		ObjectMeta: metav1.ObjectMeta{Name: "company-fr", Generation: 2},
+		Spec: autoscalev1alpha1.ScaleCalendarSpec{
+			TimeZone: "Europe/Paris",
+			Holidays: []string{"FR"},
+			Exceptions: []autoscalev1alpha1.CalendarException{
+				{Date: "2026-07-10", Window: "08:00-12:00", Action: autoscalev1alpha1.CalendarActionUp},
+			},
+		},
+	}
+	paris, _ := time.LoadLocation("Europe/Paris")
+	cal, err := newCalendar(&item.Spec, nil, nil)
+	assert.NoError(t, err)
+
+	// Bastille Day is the next holiday
+	setScaleCalendarStatus(item, cal, nil, time.Date(2026, 7, 10, 13, 0, 0, 0, paris))
+	assert.Equal(t, int64(2), item.Status.ObservedGeneration)
+	assert.Empty(t, item.Status.Action)
+	assert.Equal(t, time.Date(2026, 7, 14, 0, 0, 0, 0, paris), item.Status.NextTransitionTime.Time)
+	assert.True(t, apimeta.IsStatusConditionTrue(item.Status.Conditions, autoscalev1alpha1.ConditionReady))
+
+	setScaleCalendarStatus(item, cal, nil, time.Date(2026, 7, 10, 9, 0, 0, 0, paris))
+	assert.Equal(t, autoscalev1alpha1.CalendarActionUp, item.Status.Action)
+	assert.Equal(t, time.Date(2026, 7, 10, 12, 0, 0, 0, paris), item.Status.NextTransitionTime.Time)
+
+	setScaleCalendarStatus(item, nil, errors.New("import 0: not found"), time.Date(2026, 7, 10, 9, 0, 0, 0, paris))
+	assert.Empty(t, item.Status.Action)
+	assert.Nil(t, item.Status.NextTransitionTime)
+	ready := apimeta.FindStatusCondition(item.Status.Conditions, autoscalev1alpha1.ConditionReady)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, "import 0: not found", ready.Message)
+}
+
+func TestSetScaleOverrideStatus(t *testing.T) {
+	override := &autoscalev1alpha1.ScaleOverride{ObjectMeta: metav1.ObjectMeta{Name: "demo", Generation: 1}}
+
+	setScaleOverrideStatus(override, autoscalev1alpha1.OverridePhaseInvalid, nil,
+		errors.New("exactly one of targetRef or target must be set"))
+	assert.Equal(t, autoscalev1alpha1.OverridePhaseInvalid, override.Status.Phase)
+	assert.Equal(t, "exactly one of targetRef or target must be set", override.Status.Message)
+
+	override.Generation = 2
+	setScaleOverrideStatus(override, autoscalev1alpha1.OverridePhaseActive, []autoscalev1alpha1.TargetStatus{
+		{Kind: autoscalev1alpha1.KindDeployment, Name: "api", Phase: autoscalev1alpha1.TargetPhaseUp},
+	}, nil)
+	assert.Equal(t, autoscalev1alpha1.ScaleOverrideStatus{
+		ObservedGeneration: 2,
+		Phase:              autoscalev1alpha1.OverridePhaseActive,
+		MatchedTargets:     1,
+	}, override.Status)
+
+	setScaleOverrideStatus(override, autoscalev1alpha1.OverridePhaseExpired, nil, nil)
+	assert.Equal(t, autoscalev1alpha1.OverridePhaseExpired, override.Status.Phase)
+	assert.Equal(t, int32(0), override.Status.MatchedTargets)
+}
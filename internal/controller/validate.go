@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cicd-toolkit/kubescale/internal/schedule"
+)
+
+// ValidateAnnotations checks every kubescale annotation in annotations and
+// returns one error per malformed value, naming the annotation and the
+// offending part.
+func ValidateAnnotations(annotations map[string]string) error {
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := ValidateAnnotation(key, annotations[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateAnnotation checks a single annotation. Keys outside kubescale/
+// and kubescale annotations without syntax are always valid.
+func ValidateAnnotation(key, value string) error {
+	var err error
+	switch key {
+	case UptimeAnnotation, DowntimeAnnotation:
+		err = schedule.Validate(value)
+	case ExcludeAnnotation:
+		if v := strings.ToLower(value); v != "true" && v != "false" {
+			err = fmt.Errorf("invalid value %q, expected \"true\" or \"false\"", value)
+		}
+	case ExcludeUntilAnnotation, UpUntilAnnotation, DownUntilAnnotation:
+		if _, perr := time.Parse(time.RFC3339, value); perr != nil {
+			err = fmt.Errorf("invalid timestamp %q, expected RFC3339 such as \"2025-04-23T08:00:00Z\"", value)
+		}
+	case UpDurationAnnotation, DownDurationAnnotation:
+		_, err = parseDurationRequest(value, time.Now(), nil)
+	case TimezoneAnnotation:
+		if _, perr := time.LoadLocation(value); perr != nil || value == "" {
+			err = fmt.Errorf("unknown timezone %q, expected an IANA name such as \"Europe/Paris\"", value)
+		}
+	case ProfilesAnnotation:
+		err = validateProfiles(value)
+	case CustomReplicaAnnotation:
+		_, err = parseReplicaTarget(value)
+	case ReplicaRoundingAnnotation:
+		switch strings.ToLower(value) {
+		case "up", "down", "nearest":
+		default:
+			err = fmt.Errorf("invalid value %q, expected Up, Down or Nearest", value)
+		}
+	case MinReplicasAnnotation:
+		if n, perr := strconv.Atoi(value); perr != nil || n < 0 {
+			err = fmt.Errorf("invalid replica count %q, expected a non-negative integer", value)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAnnotation(t *testing.T) {
+	tests := []struct {
+		key      string
+		value    string
+		errorMsg string
+	}{
+		{UptimeAnnotation, "Mon-Fri 08:00-18:00 Europe/Paris", ""},
+		{UptimeAnnotation, "08:00-18:00", ""},
+		{DowntimeAnnotation, "sat-sun 00:00-23:59", ""},
+		{UptimeAnnotation, "Mon-Fir 8:00-18:00", `kubescale/uptime: column 5: unknown day "Fir"`},
+		{UptimeAnnotation, "Mon-Fri 08:00-25:00", `kubescale/uptime: column 15: invalid time "25:00"`},
+		{UptimeAnnotation, "Mon-Fri 08:00-18:00 Europe/Pari", `kubescale/uptime: column 21: unknown timezone "Europe/Pari"`},
+		{DowntimeAnnotation, "weekends", `kubescale/downtime: column 1: unknown day "weekends"`},
+		{UptimeAnnotation, "@office-hours-eu", ""},
+		{UptimeAnnotation, "Mon-Fri 08:00-12:00, Mon-Fri 13:00-18:00 Europe/Paris", ""},
+		{UptimeAnnotation, "Mon-Fri 08:00-12:00, Sat-Sun 10:00-1200", `kubescale/uptime: window 2 "Sat-Sun 10:00-1200": column 15: invalid time "1200"`},
+		{DowntimeAnnotation, ",", `kubescale/downtime: invalid format ","`},
+		{UptimeAnnotation, "cron(0 7 * * 1-5; 0 19 * * 1-5) Europe/Paris", ""},
+		{DowntimeAnnotation, "cron(0 7 * * 1-5)", `kubescale/downtime: invalid format "cron(0 7 * * 1-5)"`},
+		{UptimeAnnotation, "cron(0 7 * * 1-5; 0 25 * * 1-5)", `kubescale/uptime: invalid cron stop: hour: invalid value "25"`},
+		{DowntimeAnnotation, "2026-12-23 18:00 - 2027-01-02 07:00 Europe/Berlin, Sat-Sun 00:00-23:59", ""},
+		{DowntimeAnnotation, "2026-12-23 18:00 - 2026-12-23 08:00", `kubescale/downtime: end 2026-12-23 08:00 is not after start 2026-12-23 18:00`},
+		{UptimeAnnotation, "Mon,Wed,Fri 8:00-18:00, Saturday-sunday 10:00-12:00", ""},
+		{UptimeAnnotation, "last 3 workdays of month 08:00-20:00, first Mon of quarter 07:00-19:00 Europe/Paris", ""},
+		{DowntimeAnnotation, "Q5 00:00-23:59", `kubescale/downtime: column 1: unknown quarter "Q5"`},
+		{UptimeAnnotation, "Mon-Fri 08:00-18:00 UTC here", `kubescale/uptime: column 25: expected end of input, found "here"`},
+		{DowntimeAnnotation, "Fri 20:00-Mon 07:00 Europe/Paris", ""},
+		{UptimeAnnotation, "@office hours", `kubescale/uptime: invalid preset name "office hours"`},
+		{ExcludeAnnotation, "TRUE", ""},
+		{ExcludeAnnotation, "ture", `kubescale/exclude: invalid value "ture"`},
+		{ExcludeUntilAnnotation, "2025-04-23T08:00:00Z", ""},
+		{ExcludeUntilAnnotation, "2025-04-23 08:00", `kubescale/exclude-until: invalid timestamp "2025-04-23 08:00"`},
+		{UpDurationAnnotation, "5h", ""},
+		{DownDurationAnnotation, "5y", `kubescale/down: invalid duration "5y"`},
+		{UpDurationAnnotation, "3d Asia/Singapore", ""},
+		{UpDurationAnnotation, "1h30m", ""},
+		{DownDurationAnnotation, "P1DT4H", ""},
+		{DownDurationAnnotation, "0s", `kubescale/down: invalid duration "0s", expected a positive duration`},
+		{UpDurationAnnotation, "3d Mars/Olympus", `kubescale/up: unknown timezone "Mars/Olympus"`},
+		{UpUntilAnnotation, "2025-06-05T12:00:00+02:00", ""},
+		{DownUntilAnnotation, "tomorrow", `kubescale/down-until: invalid timestamp "tomorrow"`},
+		{TimezoneAnnotation, "Asia/Tokyo", ""},
+		{TimezoneAnnotation, "CET+1", `kubescale/timezone: unknown timezone "CET+1"`},
+		{ProfilesAnnotation, "6: Mon-Fri 08:00-18:00; 2: Mon-Fri 18:00-22:00", ""},
+		{ProfilesAnnotation, "six: Mon-Fri 08:00-18:00", `kubescale/profiles: invalid replica count "six"`},
+		{CustomReplicaAnnotation, "-1", `kubescale/replicas: invalid replica count "-1"`},
+		{CustomReplicaAnnotation, "25%", ""},
+		{CustomReplicaAnnotation, "25 %", `kubescale/replicas: invalid replica count "25 %"`},
+		{ReplicaRoundingAnnotation, "Nearest", ""},
+		{ReplicaRoundingAnnotation, "half-up", `kubescale/replicas-rounding: invalid value "half-up", expected Up, Down or Nearest`},
+		{MinReplicasAnnotation, "1", ""},
+		{MinReplicasAnnotation, "-1", `kubescale/min-replicas: invalid replica count "-1"`},
+		{ProfilesAnnotation, "6: Mon-Fri 08:00-18:00; 50%: @evening", ""},
+		{"example.com/other", "anything", ""},
+	}
+
+	for _, test := range tests {
+		err := ValidateAnnotation(test.key, test.value)
+		if test.errorMsg == "" {
+			assert.NoError(t, err, "did not expect an error for %s: %s", test.key, test.value)
+		} else if assert.Error(t, err, "expected an error for %s: %s", test.key, test.value) {
+			assert.Contains(t, err.Error(), test.errorMsg)
+		}
+	}
+}
+
+func TestValidateAnnotations(t *testing.T) {
+	err := ValidateAnnotations(map[string]string{
+		UptimeAnnotation:       "Mon-Fir 08:00-18:00",
+		ExcludeUntilAnnotation: "tomorrow",
+		DowntimeAnnotation:     "Sat-Sun 00:00-23:59",
+	})
+	assert.EqualError(t, err, `kubescale/exclude-until: invalid timestamp "tomorrow", expected RFC3339 such as "2025-04-23T08:00:00Z"`+"\n"+
+		`kubescale/uptime: column 5: unknown day "Fir", expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun`)
+	assert.NoError(t, ValidateAnnotations(nil))
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package duration parses the durations accepted by kubescale annotations
+// and resources, in Go-style compound form ("1h30m", "1.5h", "2d12h") or
+// ISO-8601 form ("P1DT4H").
+package duration
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Day, Week, Month and Year are the lengths of the calendar units. They are
+// fixed: a day is always 24 hours, a month 30 days and a year 365 days.
+const (
+	Day   = 24 * time.Hour
+	Week  = 7 * Day
+	Month = 30 * Day
+	Year  = 365 * Day
+)
+
+// units are the suffixes of the compound form. Like in Go "m" is a minute,
+// and "M" is a month.
+var units = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  Day,
+	"w":  Week,
+	"M":  Month,
+}
+
+// Parse parses a non-negative duration such as "90s", "1h30m", "1.5h", "2w",
+// "1M" or "P1DT4H".
+func Parse(s string) (time.Duration, error) {
+	var (
+		d   time.Duration
+		err error
+	)
+	if strings.HasPrefix(s, "P") {
+		d, err = parseISO(s)
+	} else {
+		d, err = parseCompound(s)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, expected e.g. 90m, 1h30m, 1.5h, 2d or P1DT4H", s)
+	}
+	return d, nil
+}
+
+// parseCompound parses a sequence of numbers, each followed by a unit.
+func parseCompound(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	var total float64
+	for s != "" {
+		n, rest, err := number(s)
+		if err != nil {
+			return 0, err
+		}
+		i := 0
+		for i < len(rest) && !isNumberByte(rest[i]) {
+			i++
+		}
+		unit, ok := units[rest[:i]]
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q", rest[:i])
+		}
+		total += n * float64(unit)
+		s = rest[i:]
+	}
+	return toDuration(total)
+}
+
+// isoDesignators are the ISO-8601 designators, before and after "T".
+var isoDesignators = [2]map[byte]time.Duration{
+	{'Y': Year, 'M': Month, 'W': Week, 'D': Day},
+	{'H': time.Hour, 'M': time.Minute, 'S': time.Second},
+}
+
+// parseISO parses an ISO-8601 duration, "PnYnMnWnDTnHnMnS" with every part
+// optional and in that order.
+func parseISO(s string) (time.Duration, error) {
+	rest := strings.TrimPrefix(s, "P")
+	if rest == "" || strings.HasSuffix(rest, "T") {
+		return 0, fmt.Errorf("empty duration")
+	}
+	var total float64
+	part, last := 0, -1
+	for rest != "" {
+		if rest[0] == 'T' {
+			if part == 1 {
+				return 0, fmt.Errorf("repeated T")
+			}
+			part, last, rest = 1, -1, rest[1:]
+			continue
+		}
+		n, after, err := number(strings.Replace(rest, ",", ".", 1))
+		if err != nil || after == "" {
+			return 0, fmt.Errorf("expected a number and a designator")
+		}
+		unit, ok := isoDesignators[part][after[0]]
+		if !ok {
+			return 0, fmt.Errorf("unknown designator %q", after[0])
+		}
+		// Designators must come in the order of isoDesignators
+		order := strings.IndexByte([2]string{"YMWD", "HMS"}[part], after[0])
+		if order <= last {
+			return 0, fmt.Errorf("designator %q out of order", after[0])
+		}
+		last = order
+		total += n * float64(unit)
+		rest = after[1:]
+	}
+	return toDuration(total)
+}
+
+// number parses the unsigned decimal number s starts with.
+func number(s string) (float64, string, error) {
+	i := 0
+	for i < len(s) && isNumberByte(s[i]) {
+		i++
+	}
+	if i == 0 || s[:i] == "." {
+		return 0, "", fmt.Errorf("expected a number")
+	}
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, s[i:], nil
+}
+
+func isNumberByte(b byte) bool {
+	return b == '.' || (b >= '0' && b <= '9')
+}
+
+// toDuration rounds total nanoseconds to a Duration. float64(math.MaxInt64)
+// rounds up to 2^63, which no longer fits, so it is out of range as well.
+func toDuration(total float64) (time.Duration, error) {
+	if total >= math.MaxInt64 {
+		return 0, fmt.Errorf("duration out of range")
+	}
+	return time.Duration(math.Round(total)), nil
+}
@@ -0,0 +1,73 @@
+package duration
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"10m", 10 * time.Minute},
+		{"2h", 2 * time.Hour},
+		{"1d", 24 * time.Hour},
+		{"90s", 90 * time.Second},
+		{"1h30m", 90 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"2d12h", 60 * time.Hour},
+		{"1w", 7 * 24 * time.Hour},
+		{"1M", 30 * 24 * time.Hour},
+		{"500ms", 500 * time.Millisecond},
+		{"0s", 0},
+		{"P1DT4H", 28 * time.Hour},
+		{"PT90M", 90 * time.Minute},
+		{"PT1.5H", 90 * time.Minute},
+		{"PT0,5H", 30 * time.Minute},
+		{"P2W", 14 * 24 * time.Hour},
+		{"P1Y", 365 * 24 * time.Hour},
+		{"P1M", 30 * 24 * time.Hour},
+		{"PT1M", time.Minute},
+		{"P1Y2M3DT4H5M6S", 365*24*time.Hour + 60*24*time.Hour + 3*24*time.Hour + 4*time.Hour + 5*time.Minute + 6*time.Second},
+	}
+	for _, test := range tests {
+		result, err := Parse(test.input)
+		if assert.NoError(t, err, "did not expect an error for input: %s", test.input) {
+			assert.Equal(t, test.expected, result, "unexpected result for input: %s", test.input)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"", "m", "5x", "5", "-1h", "+1h", "1h 30m", "h1", ".h", "1..5h",
+		"P", "PT", "P1H", "PT1D", "P1DT", "P1D1Y", "PT1S1M", "P1DTT1H", "P1", "10000000000h",
+	} {
+		_, err := Parse(input)
+		assert.Error(t, err, "expected an error for input: %s", input)
+	}
+
+	_, err := Parse("3 days")
+	assert.EqualError(t, err, `invalid duration "3 days", expected e.g. 90m, 1h30m, 1.5h, 2d or P1DT4H`)
+}
+
+func TestParseRange(t *testing.T) {
+	// 2^63ns does not fit in a Duration, the float64 just below it does
+	_, err := toDuration(math.Exp2(63))
+	assert.EqualError(t, err, "duration out of range")
+	_, err = toDuration(math.MaxInt64)
+	assert.EqualError(t, err, "duration out of range")
+	d, err := toDuration(math.Nextafter(math.Exp2(63), 0))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(1<<63-1024), d)
+
+	_, err = Parse("9223372036854775808ns")
+	assert.Error(t, err)
+	d, err = Parse("9223372036854774784ns")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(1<<63-1024), d)
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronLookbackDays bounds how far back cronExpr.prev searches. Five years
+// covers every expression that fires at least once a year, leap days included.
+const cronLookbackDays = 5 * 366
+
+var cronWindowRegex = regexp.MustCompile(`(?i)^cron\(([^;()]*);([^;()]*)\)(?:\s+(\S+))?$`)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// cronField is the set of values a cron field matches, one bit per value.
+type cronField uint64
+
+func (f cronField) has(v int) bool {
+	return f&(1<<uint(v)) != 0
+}
+
+// cronExpr is a five-field cron expression, as used by CronJob schedules.
+type cronExpr struct {
+	minute, hour, dom, month, dow cronField
+	// domStar and dowStar record an unrestricted day field: when both day
+	// fields are restricted, a day matching either of them matches.
+	domStar, dowStar bool
+}
+
+// parseCron parses "minute hour day-of-month month day-of-week" or one of
+// the @yearly, @monthly, @weekly, @daily and @hourly macros.
+func parseCron(expr string) (*cronExpr, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in cron expression %q, got %d", expr, len(fields))
+	}
+
+	c := &cronExpr{}
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	// 7 is accepted for Sunday, like in crontab
+	if c.dow, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	if c.dow.has(7) {
+		c.dow |= 1
+	}
+	c.domStar = fields[2] == "*" || fields[2] == "?"
+	c.dowStar = fields[4] == "*" || fields[4] == "?"
+	return c, nil
+}
+
+// parseCronField parses a comma-separated list of "*", "n", "n-m", each
+// optionally followed by "/step".
+func parseCronField(field string, lo, hi int, names map[string]int) (cronField, error) {
+	var set cronField
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		start, end := lo, hi
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		default:
+			first, last, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseCronValue(first, lo, hi, names); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = parseCronValue(last, lo, hi, names); err != nil {
+					return 0, err
+				}
+				if end < start {
+					return 0, fmt.Errorf("invalid range %q", rangePart)
+				}
+			} else if hasStep {
+				// "n/step" runs from n to the end of the field
+				end = hi
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func parseCronValue(s string, lo, hi int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < lo || v > hi {
+		return 0, fmt.Errorf("invalid value %q, expected %d-%d", s, lo, hi)
+	}
+	return v, nil
+}
+
+// matchesDay reports whether c fires on the day of t.
+func (c *cronExpr) matchesDay(t time.Time) bool {
+	if !c.month.has(int(t.Month())) {
+		return false
+	}
+	dom, dow := c.dom.has(t.Day()), c.dow.has(int(t.Weekday()))
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dow
+	case c.dowStar:
+		return dom
+	}
+	return dom || dow
+}
+
+// prev returns the latest time at or before t at which c fires, in the
+// location of t.
+func (c *cronExpr) prev(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	y, m, d := t.Date()
+	for i := 0; i < cronLookbackDays; i++ {
+		day := time.Date(y, m, d-i, 0, 0, 0, 0, time.UTC)
+		if !c.matchesDay(day) {
+			continue
+		}
+		for h := 23; h >= 0; h-- {
+			if !c.hour.has(h) {
+				continue
+			}
+			for min := 59; min >= 0; min-- {
+				if !c.minute.has(min) {
+					continue
+				}
+				at := WallClock(day.Year(), day.Month(), day.Day(), h*60+min, loc)
+				if !at.After(t) {
+					return at, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// next returns the first time strictly after t at which c fires, in the
+// location of t.
+func (c *cronExpr) next(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	y, m, d := t.Date()
+	for i := 0; i < cronLookbackDays; i++ {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, time.UTC)
+		if !c.matchesDay(day) {
+			continue
+		}
+		for h := 0; h < 24; h++ {
+			if !c.hour.has(h) {
+				continue
+			}
+			for min := 0; min < 60; min++ {
+				if !c.minute.has(min) {
+					continue
+				}
+				at := WallClock(day.Year(), day.Month(), day.Day(), h*60+min, loc)
+				if at.After(t) {
+					return at, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// cronWindow is in range from each firing of start until the next firing of
+// stop.
+type cronWindow struct {
+	start, stop *cronExpr
+	location    *time.Location
+}
+
+// isCronWindow reports whether value uses the cron window syntax.
+func isCronWindow(value string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(value)), "cron(")
+}
+
+// parseCronWindow parses "cron(<start>; <stop>) [Timezone]", in loc
+// without a timezone.
+func parseCronWindow(value string, loc *time.Location) (*cronWindow, error) {
+	matches := cronWindowRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return nil, fmt.Errorf("invalid format %q, expected \"cron(<start>; <stop>) [Timezone]\"", value)
+	}
+	start, err := parseCron(matches[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron start: %w", err)
+	}
+	stop, err := parseCron(matches[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron stop: %w", err)
+	}
+	if tz := matches[3]; tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("unknown timezone %q, expected an IANA name such as \"Europe/Paris\"", tz)
+		}
+	}
+	return &cronWindow{start: start, stop: stop, location: loc}, nil
+}
+
+// Active reports whether start fired more recently than stop at t.
+func (w *cronWindow) Active(t time.Time) bool {
+	t = t.In(w.location)
+	started, ok := w.start.prev(t)
+	if !ok {
+		return false
+	}
+	stopped, ok := w.stop.prev(t)
+	return !ok || started.After(stopped)
+}
+
+// NextTransition returns the next firing of stop while the window is
+// active, and of start otherwise.
+func (w *cronWindow) NextTransition(t time.Time) (time.Time, bool) {
+	if w.Active(t) {
+		return w.stop.next(t.In(w.location))
+	}
+	return w.start.next(t.In(w.location))
+}
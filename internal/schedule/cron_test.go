@@ -0,0 +1,93 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr     string
+		errorMsg string
+	}{
+		{"0 7 * * 1-5", ""},
+		{"*/15 8-18 * * mon-fri", ""},
+		{"30 6 1,15 jan-jun 0,7", ""},
+		{"@daily", ""},
+		{"0 7 * *", `expected 5 fields in cron expression "0 7 * *", got 4`},
+		{"0 24 * * *", `hour: invalid value "24", expected 0-23`},
+		{"0 7 * * fri-mon", `day of week: invalid range "fri-mon"`},
+		{"*/0 7 * * *", `minute: invalid step "0"`},
+	}
+
+	for _, test := range tests {
+		_, err := parseCron(test.expr)
+		if test.errorMsg == "" {
+			assert.NoError(t, err, "did not expect an error for %s", test.expr)
+		} else {
+			assert.EqualError(t, err, test.errorMsg, "unexpected error for %s", test.expr)
+		}
+	}
+}
+
+func TestCronExprPrev(t *testing.T) {
+	tests := []struct {
+		expr     string
+		at       time.Time
+		expected time.Time
+	}{
+		// Same day, inclusive of the current minute
+		{"0 7 * * 1-5", time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC), time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)},
+		// Monday morning goes back to Friday
+		{"0 19 * * 1-5", time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC), time.Date(2025, 5, 30, 19, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 6, 2, 8, 44, 0, 0, time.UTC), time.Date(2025, 6, 2, 8, 30, 0, 0, time.UTC)},
+		// Restricted day of month and day of week match either
+		{"0 0 13 * 5", time.Date(2025, 6, 14, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 13, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		c, err := parseCron(test.expr)
+		assert.NoError(t, err)
+		prev, ok := c.prev(test.at)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, prev, "unexpected result for %s at %s", test.expr, test.at)
+	}
+
+	// A date that never exists is never found
+	c, err := parseCron("0 0 30 2 *")
+	assert.NoError(t, err)
+	_, ok := c.prev(time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
+
+func TestCronWindow(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	tests := []struct {
+		value    string
+		at       time.Time
+		expected bool
+	}{
+		{"cron(0 7 * * 1-5; 0 19 * * 1-5)", time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC), true},
+		{"cron(0 7 * * 1-5; 0 19 * * 1-5)", time.Date(2025, 6, 2, 6, 59, 0, 0, time.UTC), false},
+		{"cron(0 7 * * 1-5; 0 19 * * 1-5)", time.Date(2025, 6, 2, 19, 0, 0, 0, time.UTC), false},
+		{"cron(0 7 * * 1-5; 0 19 * * 1-5)", time.Date(2025, 6, 7, 12, 0, 0, 0, time.UTC), false},
+		// Started on Friday evening, stopped on Monday morning
+		{"cron(0 20 * * 5; 0 7 * * 1) Europe/Paris", time.Date(2025, 6, 7, 12, 0, 0, 0, paris), true},
+		{"cron(0 20 * * 5; 0 7 * * 1) Europe/Paris", time.Date(2025, 6, 9, 7, 30, 0, 0, paris), false},
+		{"cron(0 7 * * 1-5; 0 19 * * 1-5) Europe/Paris", time.Date(2025, 6, 2, 5, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, test := range tests {
+		w, err := parseCronWindow(test.value, time.UTC)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, w.Active(test.at), "unexpected result for %s at %s", test.value, test.at)
+	}
+
+	_, err := parseCronWindow("cron(0 7 * * 1-5)", time.UTC)
+	assert.ErrorContains(t, err, `expected "cron(<start>; <stop>) [Timezone]"`)
+	_, err = parseCronWindow("cron(0 7 * * 1-5; 0 19 * * 1-5) Europe/Pari", time.UTC)
+	assert.ErrorContains(t, err, `unknown timezone "Europe/Pari"`)
+}
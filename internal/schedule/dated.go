@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const datedLayout = "2006-01-02 15:04"
+
+var (
+	datedPrefixRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[ T]`)
+	datedWindowRegex = regexp.MustCompile(`^(\S+)[ T](\S+)\s+-\s+(\S+)[ T](\S+?)(?:\s+(\S+))?$`)
+)
+
+// datedWindow is a single period between two absolute timestamps, such as a
+// holiday freeze. Unlike the other windows it does not repeat.
+type datedWindow struct {
+	start, end time.Time
+}
+
+// isDatedWindow reports whether value uses the dated window syntax.
+func isDatedWindow(value string) bool {
+	return datedPrefixRegex.MatchString(strings.TrimSpace(value))
+}
+
+// parseDatedWindow parses "YYYY-MM-DD HH:MM - YYYY-MM-DD HH:MM [Timezone]",
+// in loc without a timezone.
+func parseDatedWindow(value string, loc *time.Location) (*datedWindow, error) {
+	matches := datedWindowRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return nil, fmt.Errorf("invalid format %q, expected \"YYYY-MM-DD HH:MM - YYYY-MM-DD HH:MM [Timezone]\"", value)
+	}
+	if tz := matches[5]; tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("unknown timezone %q, expected an IANA name such as \"Europe/Paris\"", tz)
+		}
+	}
+	start, err := parseDatedTime(matches[1]+" "+matches[2], loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start %q, expected YYYY-MM-DD HH:MM", matches[1]+" "+matches[2])
+	}
+	end, err := parseDatedTime(matches[3]+" "+matches[4], loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end %q, expected YYYY-MM-DD HH:MM", matches[3]+" "+matches[4])
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("end %s is not after start %s", end.Format(datedLayout), start.Format(datedLayout))
+	}
+	return &datedWindow{start: start, end: end}, nil
+}
+
+// parseDatedTime parses a datedLayout wall-clock time in loc, resolved with
+// WallClock.
+func parseDatedTime(value string, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse(datedLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return WallClock(t.Year(), t.Month(), t.Day(), clock(t), loc), nil
+}
+
+// Active reports whether t is at or after start and before end.
+func (w *datedWindow) Active(t time.Time) bool {
+	return !t.Before(w.start) && t.Before(w.end)
+}
+
+// NextTransition returns start or end, whichever comes next.
+func (w *datedWindow) NextTransition(t time.Time) (time.Time, bool) {
+	switch {
+	case t.Before(w.start):
+		return w.start, true
+	case t.Before(w.end):
+		return w.end, true
+	}
+	return time.Time{}, false
+}
+
+// expired reports whether the window ended at or before now.
+func (w *datedWindow) expired(now time.Time) bool {
+	return !now.Before(w.end)
+}
+
+// Expired returns the dated windows of an uptime or downtime value
+// that ended at or before now, reading windows without a timezone in loc,
+// UTC when nil. They no longer have any effect and can be removed.
+// Malformed windows are left to Validate.
+func Expired(value string, now time.Time, loc *time.Location) []string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var expired []string
+	for _, entry := range Split(value) {
+		if !isDatedWindow(entry) {
+			continue
+		}
+		if w, err := parseDatedWindow(entry, loc); err == nil && w.expired(now) {
+			expired = append(expired, entry)
+		}
+	}
+	return expired
+}
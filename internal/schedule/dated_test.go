@@ -0,0 +1,66 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatedWindow(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	w, err := parseDatedWindow("2026-12-23 18:00 - 2027-01-02 07:00 Europe/Berlin", time.UTC)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		at       time.Time
+		expected bool
+	}{
+		{time.Date(2026, 12, 23, 17, 59, 0, 0, berlin), false},
+		{time.Date(2026, 12, 23, 18, 0, 0, 0, berlin), true},
+		{time.Date(2026, 12, 23, 17, 30, 0, 0, time.UTC), true},
+		{time.Date(2027, 1, 2, 6, 59, 0, 0, berlin), true},
+		{time.Date(2027, 1, 2, 7, 0, 0, 0, berlin), false},
+		{time.Date(2027, 12, 24, 12, 0, 0, 0, berlin), false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, w.Active(test.at), "unexpected result at %s", test.at)
+	}
+
+	w, err = parseDatedWindow("2026-12-24T00:00 - 2026-12-27T00:00", time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, w.start.Location())
+}
+
+func TestParseDatedWindowErrors(t *testing.T) {
+	tests := []struct {
+		value    string
+		errorMsg string
+	}{
+		{"2026-12-23 18:00", `invalid format "2026-12-23 18:00"`},
+		{"2026-12-32 18:00 - 2027-01-02 07:00", `invalid start "2026-12-32 18:00"`},
+		{"2026-12-23 18:00 - 2027-01-02 7h", `invalid end "2027-01-02 7h"`},
+		{"2027-01-02 07:00 - 2026-12-23 18:00", `end 2026-12-23 18:00 is not after start 2027-01-02 07:00`},
+		{"2026-12-23 18:00 - 2027-01-02 07:00 Europe/Berln", `unknown timezone "Europe/Berln"`},
+	}
+	for _, test := range tests {
+		_, err := parseDatedWindow(test.value, time.UTC)
+		assert.ErrorContains(t, err, test.errorMsg, "unexpected error for %s", test.value)
+	}
+}
+
+func TestDatedWindowCombined(t *testing.T) {
+	s, err := Parse("Sat-Sun 00:00-23:59, 2026-12-23 18:00 - 2027-01-02 07:00 Europe/Berlin", nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, s.Active(time.Date(2026, 12, 29, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, s.Active(time.Date(2027, 1, 9, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, s.Active(time.Date(2027, 1, 5, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestExpired(t *testing.T) {
+	value := "Mon-Fri 08:00-18:00, 2025-12-23 18:00 - 2026-01-02 07:00 Europe/Berlin, 2026-12-23 18:00 - 2027-01-02 07:00 Europe/Berlin"
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"2025-12-23 18:00 - 2026-01-02 07:00 Europe/Berlin"}, Expired(value, now, nil))
+	assert.Empty(t, Expired(value, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), nil))
+	assert.Empty(t, Expired("", now, nil))
+}
@@ -0,0 +1,137 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWallClock(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	newYork, _ := time.LoadLocation("America/New_York")
+	santiago, _ := time.LoadLocation("America/Santiago")
+	tests := []struct {
+		name     string
+		year     int
+		month    time.Month
+		day      int
+		minute   int
+		loc      *time.Location
+		expected string
+	}{
+		{"regular", 2025, 6, 2, 8 * 60, paris, "2025-06-02T06:00:00Z"},
+		{"skipped resolves to the end of the gap", 2025, 3, 30, 2*60 + 30, paris, "2025-03-30T01:00:00Z"},
+		{"repeated resolves to the first occurrence", 2025, 10, 26, 2*60 + 30, paris, "2025-10-26T00:30:00Z"},
+		{"after the repeated hour", 2025, 10, 26, 3 * 60, paris, "2025-10-26T02:00:00Z"},
+		{"repeated hour in America", 2025, 11, 2, 60 + 30, newYork, "2025-11-02T05:30:00Z"},
+		{"skipped midnight", 2025, 9, 7, 0, santiago, "2025-09-07T04:00:00Z"},
+		{"repeated hour before midnight", 2025, 4, 5, 23 * 60, santiago, "2025-04-06T02:00:00Z"},
+	}
+	for _, test := range tests {
+		expected, _ := time.Parse(time.RFC3339, test.expected)
+		got := WallClock(test.year, test.month, test.day, test.minute, test.loc)
+		assert.True(t, expected.Equal(got), "unexpected instant for test case %s: %s", test.name, got.UTC())
+	}
+}
+
+func TestDSTMatrix(t *testing.T) {
+	tests := []struct {
+		zone     string
+		date     string
+		window   string
+		expected time.Duration
+	}{
+		// Europe: last Sundays of March and October
+		{"Europe/Paris", "2025-03-30", "00:00-12:00", 11 * time.Hour},
+		{"Europe/Paris", "2025-10-26", "00:00-12:00", 13 * time.Hour},
+		{"Europe/Paris", "2025-03-30", "02:00-03:00", 0},
+		{"Europe/Paris", "2025-10-26", "02:00-03:00", 2 * time.Hour},
+		{"Europe/London", "2025-03-30", "00:00-12:00", 11 * time.Hour},
+		{"Europe/London", "2025-10-26", "00:00-12:00", 13 * time.Hour},
+		{"Europe/Helsinki", "2025-03-30", "00:00-12:00", 11 * time.Hour},
+		{"Europe/Helsinki", "2025-10-26", "00:00-12:00", 13 * time.Hour},
+		// America: second Sunday of March, first of November
+		{"America/New_York", "2025-03-09", "00:00-12:00", 11 * time.Hour},
+		{"America/New_York", "2025-11-02", "00:00-12:00", 13 * time.Hour},
+		{"America/Los_Angeles", "2025-03-09", "00:00-12:00", 11 * time.Hour},
+		{"America/Los_Angeles", "2025-11-02", "00:00-12:00", 13 * time.Hour},
+		{"America/New_York", "2025-11-02", "22:00-02:00", 5 * time.Hour},
+		// No DST any more
+		{"America/Sao_Paulo", "2025-03-09", "00:00-12:00", 12 * time.Hour},
+		// Southern hemisphere: clocks go back in April and forward in spring
+		{"Australia/Sydney", "2025-04-06", "00:00-12:00", 13 * time.Hour},
+		{"Australia/Sydney", "2025-10-05", "00:00-12:00", 11 * time.Hour},
+		{"Pacific/Auckland", "2025-04-06", "00:00-12:00", 13 * time.Hour},
+		{"Pacific/Auckland", "2025-09-28", "00:00-12:00", 11 * time.Hour},
+		// Santiago changes at midnight, so the day itself is shorter or longer
+		{"America/Santiago", "2025-09-07", "00:00-12:00", 11 * time.Hour},
+		{"America/Santiago", "2025-04-05", "20:00-00:00", 5 * time.Hour},
+		// Lord Howe moves by half an hour
+		{"Australia/Lord_Howe", "2025-04-06", "00:00-12:00", 12*time.Hour + 30*time.Minute},
+		{"Australia/Lord_Howe", "2025-10-05", "00:00-12:00", 11*time.Hour + 30*time.Minute},
+	}
+	for _, test := range tests {
+		loc, err := time.LoadLocation(test.zone)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		s, err := Parse(test.window+" "+test.zone, nil, nil)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		date, _ := time.Parse(time.DateOnly, test.date)
+		from := WallClock(date.Year(), date.Month(), date.Day(), 0, loc)
+		to := WallClock(date.Year(), date.Month(), date.Day()+1, 0, loc)
+		var total time.Duration
+		for _, w := range s.Windows(from, to) {
+			total += w.End.Sub(w.Start)
+		}
+		assert.Equal(t, test.expected, total, "unexpected duration for %s on %s in %s", test.window, test.date, test.zone)
+	}
+}
+
+// TestDSTNextTransition checks, minute by minute across transitions, that
+// NextTransition reports exactly the instants at which Active changes.
+func TestDSTNextTransition(t *testing.T) {
+	tests := []struct {
+		value string
+		from  string
+	}{
+		{"02:30-03:30 Europe/Paris", "2025-03-29T00:00:00Z"},
+		{"02:30-03:30 Europe/Paris", "2025-10-25T00:00:00Z"},
+		{"22:00-02:00 America/New_York", "2025-03-08T00:00:00Z"},
+		{"22:00-02:00 America/New_York", "2025-11-01T00:00:00Z"},
+		{"Sat 23:00-Sun 03:00 Australia/Sydney", "2025-04-04T00:00:00Z"},
+		{"Sat 23:00-Sun 03:00 Australia/Sydney", "2025-10-03T00:00:00Z"},
+		{"cron(30 2 * * *; 0 4 * * *) Europe/Paris", "2025-03-29T00:00:00Z"},
+		{"cron(30 2 * * *; 0 4 * * *) Europe/Paris", "2025-10-25T00:00:00Z"},
+		{"2025-03-30 02:30 - 2025-03-30 04:00 Europe/Paris", "2025-03-29T00:00:00Z"},
+	}
+	for _, test := range tests {
+		s, err := Parse(test.value, nil, nil)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		from, _ := time.Parse(time.RFC3339, test.from)
+		to := from.Add(72 * time.Hour)
+
+		var expected []time.Time
+		for at, active := from, s.Active(from); at.Before(to); at = at.Add(time.Minute) {
+			if s.Active(at) != active {
+				expected = append(expected, at)
+				active = !active
+			}
+		}
+		var got []time.Time
+		for at := from; ; {
+			next, ok := s.NextTransition(at)
+			if !ok || !next.Before(to) {
+				break
+			}
+			got = append(got, next.UTC())
+			at = next
+		}
+		assert.Equal(t, expected, got, "unexpected transitions for %s from %s", test.value, test.from)
+	}
+}
@@ -0,0 +1,327 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// The day/time window grammar, whitespace being allowed between tokens:
+//
+//	window  = [ dates ] [ days ] time "-" time [ zone ]
+//	        | day time "-" day time [ zone ]
+//	days    = item { "," item }
+//	item    = day [ "-" day ]
+//	day     = "Mon" | "Monday" | ... (any case)
+//	time    = H:MM | HH:MM
+//	zone    = IANA timezone name
+//
+// dates, the months and date rules, is described in rules.go.
+
+const expectedDays = "one of Mon, Tue, Wed, Thu, Fri, Sat, Sun"
+
+// SyntaxError is a window that does not follow the schedule grammar.
+type SyntaxError struct {
+	// Column is the 1-based position of the offending token in the window.
+	Column int
+	// Found is the offending token, empty at the end of the window.
+	Found string
+	// Expected describes what the grammar allows at Column.
+	Expected string
+	// Problem qualifies a token of the right kind with a wrong value, such
+	// as an unknown day.
+	Problem string
+}
+
+func (e *SyntaxError) Error() string {
+	found := "end of input"
+	if e.Found != "" {
+		found = strconv.Quote(e.Found)
+	}
+	if e.Problem != "" {
+		return fmt.Sprintf("column %d: %s %s, expected %s", e.Column, e.Problem, found, e.Expected)
+	}
+	return fmt.Sprintf("column %d: expected %s, found %s", e.Column, e.Expected, found)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenTime
+	tokenDash
+	tokenComma
+	tokenOther
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	column int
+}
+
+// lexer splits a window into tokens. Words are runs of letters and times
+// runs of digits and colons; any other character is a token of its own.
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *lexer) run(accept func(rune) bool) token {
+	start := l.pos
+	for l.pos < len(l.input) && accept(l.input[l.pos]) {
+		l.pos++
+	}
+	return token{text: string(l.input[start:l.pos]), column: start + 1}
+}
+
+func (l *lexer) next() token {
+	l.skipSpace()
+	if l.pos == len(l.input) {
+		return token{kind: tokenEOF, column: l.pos + 1}
+	}
+	r := l.input[l.pos]
+	switch {
+	case unicode.IsLetter(r):
+		tok := l.run(unicode.IsLetter)
+		tok.kind = tokenWord
+		return tok
+	case unicode.IsDigit(r):
+		tok := l.run(func(r rune) bool { return unicode.IsDigit(r) || r == ':' })
+		tok.kind = tokenTime
+		return tok
+	}
+	l.pos++
+	tok := token{kind: tokenOther, text: string(r), column: l.pos}
+	switch r {
+	case '-':
+		tok.kind = tokenDash
+	case ',':
+		tok.kind = tokenComma
+	}
+	return tok
+}
+
+func (l *lexer) peek() token {
+	return l.peekAt(0)
+}
+
+// peekAt returns the token after the next n ones without consuming any.
+func (l *lexer) peekAt(n int) token {
+	pos := l.pos
+	tok := l.next()
+	for ; n > 0; n-- {
+		tok = l.next()
+	}
+	l.pos = pos
+	return tok
+}
+
+// field returns the next run of non-space characters, used for timezone
+// names which mix letters, digits and punctuation.
+func (l *lexer) field() token {
+	l.skipSpace()
+	tok := l.run(func(r rune) bool { return !unicode.IsSpace(r) })
+	tok.kind = tokenWord
+	return tok
+}
+
+// weekdaySet is a set of days of the week, one bit per time.Weekday.
+type weekdaySet uint8
+
+const everyDay weekdaySet = 1<<7 - 1
+
+func (s weekdaySet) has(d time.Weekday) bool {
+	return s&(1<<uint(d)) != 0
+}
+
+// weekdayRange returns the days from first to last, wrapping around Sunday
+// when last comes before first.
+func weekdayRange(first, last time.Weekday) weekdaySet {
+	var s weekdaySet
+	for d := first; ; d = (d + 1) % 7 {
+		s |= 1 << uint(d)
+		if d == last {
+			return s
+		}
+	}
+}
+
+type scheduleParser struct {
+	lex lexer
+	// loc is the timezone of a window without one.
+	loc *time.Location
+}
+
+// parseTimeRange parses a complete day/time window: a *TimeRange, or
+// a *weekSpan when both ends carry a day. Without a timezone it is read in
+// loc.
+func parseTimeRange(value string, loc *time.Location) (Window, error) {
+	p := &scheduleParser{lex: lexer{input: []rune(value)}, loc: loc}
+	tr := &TimeRange{Days: everyDay, Location: loc}
+
+	var err error
+	if tr.Dates, err = p.dates(); err != nil {
+		return nil, err
+	}
+
+	first := p.lex.peek()
+	switch first.kind {
+	case tokenWord:
+		days, err := p.days()
+		if err != nil {
+			return nil, err
+		}
+		tr.Days = days
+	case tokenTime:
+	default:
+		return nil, unexpected(first, "a day or a time")
+	}
+
+	if tr.Start, err = p.time(); err != nil {
+		return nil, err
+	}
+	if tok := p.lex.next(); tok.kind != tokenDash {
+		return nil, unexpected(tok, `"-"`)
+	}
+
+	if end := p.lex.peek(); end.kind == tokenWord {
+		if tr.Dates != nil {
+			return nil, unexpected(end, "a time as H:MM")
+		}
+		// A day on both ends makes one continuous interval on the week
+		if first.kind != tokenWord || bits.OnesCount8(uint8(tr.Days)) != 1 {
+			return nil, &SyntaxError{Column: first.column, Found: first.text,
+				Expected: "a single start day when the end has a day", Problem: "invalid start"}
+		}
+		span := &weekSpan{
+			startDay: time.Weekday(bits.TrailingZeros8(uint8(tr.Days))),
+			start:    tr.Start,
+		}
+		if span.endDay, err = p.day(); err != nil {
+			return nil, err
+		}
+		if span.end, err = p.time(); err != nil {
+			return nil, err
+		}
+		if span.location, err = p.zone(); err != nil {
+			return nil, err
+		}
+		return span, nil
+	}
+
+	if tr.End, err = p.time(); err != nil {
+		return nil, err
+	}
+	if tr.Location, err = p.zone(); err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// zone parses the optional timezone ending a window, p.loc when absent.
+func (p *scheduleParser) zone() (*time.Location, error) {
+	if p.lex.peek().kind == tokenEOF {
+		return p.loc, nil
+	}
+	zone := p.lex.field()
+	loc, err := time.LoadLocation(zone.text)
+	if err != nil {
+		return nil, &SyntaxError{Column: zone.column, Found: zone.text,
+			Expected: `an IANA name such as "Europe/Paris"`, Problem: "unknown timezone"}
+	}
+	if tok := p.lex.next(); tok.kind != tokenEOF {
+		return nil, unexpected(tok, "end of input")
+	}
+	return loc, nil
+}
+
+// days parses a list of days and day ranges.
+func (p *scheduleParser) days() (weekdaySet, error) {
+	var days weekdaySet
+	for {
+		first, err := p.day()
+		if err != nil {
+			return 0, err
+		}
+		last := first
+		if p.lex.peek().kind == tokenDash {
+			p.lex.next()
+			if last, err = p.day(); err != nil {
+				return 0, err
+			}
+		}
+		days |= weekdayRange(first, last)
+
+		if p.lex.peek().kind != tokenComma {
+			return days, nil
+		}
+		p.lex.next()
+	}
+}
+
+func (p *scheduleParser) day() (time.Weekday, error) {
+	tok := p.lex.next()
+	if tok.kind != tokenWord {
+		return 0, unexpected(tok, "a day, "+expectedDays)
+	}
+	day, ok := parseWeekday(tok.text)
+	if !ok {
+		return 0, &SyntaxError{Column: tok.column, Found: tok.text, Expected: expectedDays, Problem: "unknown day"}
+	}
+	return day, nil
+}
+
+func (p *scheduleParser) time() (time.Time, error) {
+	tok := p.lex.next()
+	if tok.kind != tokenTime {
+		return time.Time{}, unexpected(tok, "a time as H:MM")
+	}
+	t, err := parseHourMin(tok.text)
+	if err != nil {
+		return time.Time{}, &SyntaxError{Column: tok.column, Found: tok.text, Expected: "H:MM", Problem: "invalid time"}
+	}
+	return t, nil
+}
+
+func unexpected(tok token, expected string) *SyntaxError {
+	return &SyntaxError{Column: tok.column, Found: tok.text, Expected: expected}
+}
+
+// parseWeekday accepts abbreviated and full day names in any case.
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(s)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
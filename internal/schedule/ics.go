@@ -0,0 +1,668 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cicd-toolkit/kubescale/internal/duration"
+)
+
+// ParseICS parses the events of an iCalendar (RFC 5545) document into a
+// schedule that is active during each of them. Floating times and all-day
+// events are read in loc, and other times in their TZID, which must be an
+// IANA name. Recurring events are expanded from their RRULE and EXDATE, and
+// instances moved with RECURRENCE-ID replace the ones they override.
+// Cancelled events are left out. Nothing is ever fetched.
+func ParseICS(data string, loc *time.Location) (*Schedule, error) {
+	var (
+		events    []*icsEvent
+		overrides []*icsEvent
+		current   map[string]icsProperty
+		exdates   []icsProperty
+		inEvent   bool
+		nested    int
+		uids      = make(map[string]*icsEvent)
+	)
+	for i, line := range unfoldICS(data) {
+		if line == "" {
+			continue
+		}
+		prop, err := parseICSProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			inEvent, nested, current, exdates = true, 0, make(map[string]icsProperty), nil
+		case inEvent && prop.name == "BEGIN":
+			// Properties of alarms and other components of the event are ignored
+			nested++
+		case inEvent && prop.name == "END" && nested > 0:
+			nested--
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			inEvent = false
+			if strings.EqualFold(current["STATUS"].value, "CANCELLED") && current["RECURRENCE-ID"].name == "" {
+				continue
+			}
+			event, err := newICSEvent(current, exdates, loc)
+			if err != nil {
+				return nil, fmt.Errorf("event %q: %w", eventName(current), err)
+			}
+			if event.recurrenceID.IsZero() {
+				events = append(events, event)
+				uids[current["UID"].value] = event
+			} else {
+				overrides = append(overrides, event)
+			}
+		case inEvent && nested == 0:
+			if prop.name == "EXDATE" {
+				exdates = append(exdates, prop)
+			} else {
+				current[prop.name] = prop
+			}
+		}
+	}
+
+	s := &Schedule{}
+	for _, event := range events {
+		s.windows = append(s.windows, event)
+	}
+	for _, override := range overrides {
+		if master, ok := uids[override.uid]; ok {
+			master.exdates[override.recurrenceID.Unix()] = true
+		}
+		if !override.cancelled {
+			s.windows = append(s.windows, override)
+		}
+	}
+	return s, nil
+}
+
+// unfoldICS splits data into content lines, joining the lines folded onto a
+// line starting with a space or a tab.
+func unfoldICS(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return lines
+}
+
+// icsProperty is a content line: NAME;PARAM=VALUE:value.
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func parseICSProperty(line string) (icsProperty, error) {
+	head, value, ok := cutUnquoted(line, ':')
+	if !ok {
+		return icsProperty{}, fmt.Errorf("invalid content line %q, expected NAME:value", line)
+	}
+	parts := strings.Split(head, ";")
+	prop := icsProperty{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: value}
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+	return prop, nil
+}
+
+// cutUnquoted cuts s around the first sep outside double quotes.
+func cutUnquoted(s string, sep byte) (string, string, bool) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				return s[:i], s[i+1:], true
+			}
+		}
+	}
+	return s, "", false
+}
+
+func eventName(props map[string]icsProperty) string {
+	if summary := props["SUMMARY"].value; summary != "" {
+		return summary
+	}
+	return props["UID"].value
+}
+
+// icsEvent is a VEVENT, possibly recurring, as a Window. Occurrences are
+// wall-clock times in location, so that a weekly 09:00 event stays at 09:00
+// across DST changes.
+type icsEvent struct {
+	uid      string
+	start    time.Time // DTSTART as a UTC wall-clock time
+	location *time.Location
+	// days and minutes are the nominal length of each occurrence.
+	days, minutes int
+	rule          *recurrence
+	exdates       map[int64]bool
+	// recurrenceID is the start of the occurrence this event replaces.
+	recurrenceID time.Time
+	cancelled    bool
+}
+
+func newICSEvent(props map[string]icsProperty, exdates []icsProperty, loc *time.Location) (*icsEvent, error) {
+	dtstart, ok := props["DTSTART"]
+	if !ok {
+		return nil, fmt.Errorf("missing DTSTART")
+	}
+	start, location, allDay, err := parseICSTime(dtstart, loc)
+	if err != nil {
+		return nil, fmt.Errorf("DTSTART: %w", err)
+	}
+	event := &icsEvent{
+		uid:       props["UID"].value,
+		start:     start,
+		location:  location,
+		exdates:   make(map[int64]bool),
+		cancelled: strings.EqualFold(props["STATUS"].value, "CANCELLED"),
+	}
+
+	switch {
+	case props["DTEND"].name != "":
+		end, endLocation, _, err := parseICSTime(props["DTEND"], loc)
+		if err != nil {
+			return nil, fmt.Errorf("DTEND: %w", err)
+		}
+		if endLocation != location {
+			// Measure the length in the zone of the start
+			at := WallClock(end.Year(), end.Month(), end.Day(), clock(end), endLocation).In(location)
+			end = wall(at)
+		}
+		if !end.After(start) {
+			return nil, fmt.Errorf("DTEND is not after DTSTART")
+		}
+		event.days = int(date(end).Sub(date(start)).Hours() / 24)
+		event.minutes = clock(end) - clock(start)
+	case props["DURATION"].name != "":
+		if event.days, event.minutes, err = parseICSDuration(props["DURATION"].value); err != nil {
+			return nil, fmt.Errorf("DURATION: %w", err)
+		}
+	case allDay:
+		event.days = 1
+	}
+
+	if rrule, ok := props["RRULE"]; ok {
+		if event.rule, err = parseRecurrence(rrule.value, loc); err != nil {
+			return nil, fmt.Errorf("RRULE: %w", err)
+		}
+	}
+	for _, exdate := range exdates {
+		for _, value := range strings.Split(exdate.value, ",") {
+			exdate.value = value
+			at, exLocation, _, err := parseICSTime(exdate, loc)
+			if err != nil {
+				return nil, fmt.Errorf("EXDATE: %w", err)
+			}
+			event.exdates[WallClock(at.Year(), at.Month(), at.Day(), clock(at), exLocation).Unix()] = true
+		}
+	}
+	if id, ok := props["RECURRENCE-ID"]; ok {
+		at, idLocation, _, err := parseICSTime(id, loc)
+		if err != nil {
+			return nil, fmt.Errorf("RECURRENCE-ID: %w", err)
+		}
+		event.recurrenceID = WallClock(at.Year(), at.Month(), at.Day(), clock(at), idLocation)
+	}
+	return event, nil
+}
+
+// parseICSTime parses a DATE or DATE-TIME value into a UTC wall-clock time
+// and the location it is read in.
+func parseICSTime(prop icsProperty, loc *time.Location) (time.Time, *time.Location, bool, error) {
+	value := prop.value
+	if prop.params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, nil, false, fmt.Errorf("invalid date %q, expected YYYYMMDD", value)
+		}
+		return t, loc, true, nil
+	}
+	location := loc
+	if strings.HasSuffix(value, "Z") {
+		value, location = strings.TrimSuffix(value, "Z"), time.UTC
+	} else if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		if location, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, nil, false, fmt.Errorf("unknown timezone %q, expected an IANA name such as \"Europe/Paris\"", tzid)
+		}
+	}
+	t, err := time.Parse("20060102T150405", value)
+	if err != nil {
+		return time.Time{}, nil, false, fmt.Errorf("invalid date-time %q, expected YYYYMMDDTHHMMSS", prop.value)
+	}
+	return t, location, false, nil
+}
+
+// parseICSDuration parses a positive RFC 5545 duration such as "P1D" or
+// "PT1H30M" into whole days and minutes. Seconds are dropped.
+func parseICSDuration(value string) (days, minutes int, err error) {
+	d, err := duration.Parse(value)
+	if err != nil {
+		return 0, 0, err
+	}
+	if d == 0 {
+		return 0, 0, fmt.Errorf("invalid duration %q, expected a positive duration", value)
+	}
+	return int(d / duration.Day), int(d % duration.Day / time.Minute), nil
+}
+
+// date truncates the UTC wall-clock time t to its day.
+func date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// occurrence returns the instants at which the occurrence starting at the
+// UTC wall-clock time at begins and ends.
+func (e *icsEvent) occurrence(at time.Time) (time.Time, time.Time) {
+	start := WallClock(at.Year(), at.Month(), at.Day(), clock(at), e.location)
+	end := WallClock(at.Year(), at.Month(), at.Day()+e.days, clock(at)+e.minutes, e.location)
+	return start, end
+}
+
+// each calls yield with the occurrences of e in order, starting with the
+// last ones that may still be running at t, until yield returns false or
+// the occurrences pass MaxLookahead after t.
+func (e *icsEvent) each(t time.Time, yield func(start, end time.Time) bool) {
+	if e.rule == nil {
+		start, end := e.occurrence(e.start)
+		yield(start, end)
+		return
+	}
+	// Occurrences that started a whole length before t are over
+	from := wall(t.In(e.location)).AddDate(0, 0, -e.days-1)
+	limit := t.Add(MaxLookahead)
+	e.rule.each(e.start, from, wall(limit.In(e.location)), func(at time.Time) bool {
+		start, end := e.occurrence(at)
+		if start.After(limit) || (!e.rule.until.IsZero() && !start.Before(e.rule.until)) {
+			return false
+		}
+		if e.exdates[start.Unix()] {
+			return true
+		}
+		return yield(start, end)
+	})
+}
+
+// Active implements Window.
+func (e *icsEvent) Active(t time.Time) bool {
+	active := false
+	e.each(t, func(start, end time.Time) bool {
+		if start.After(t) {
+			return false
+		}
+		active = t.Before(end)
+		return !active
+	})
+	return active
+}
+
+// NextTransition implements Window with the next start or end of an
+// occurrence.
+func (e *icsEvent) NextTransition(t time.Time) (time.Time, bool) {
+	var next time.Time
+	e.each(t, func(start, end time.Time) bool {
+		for _, at := range []time.Time{start, end} {
+			if at.After(t) && (next.IsZero() || at.Before(next)) {
+				next = at
+			}
+		}
+		return !start.After(t)
+	})
+	return next, !next.IsZero()
+}
+
+// recurrence is a parsed RRULE. Supported parts are FREQ, INTERVAL, COUNT,
+// UNTIL, BYMONTH, BYMONTHDAY, BYDAY and WKST.
+type recurrence struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time // first instant after UNTIL, zero without UNTIL
+	byMonth    []time.Month
+	byMonthDay []int
+	byDay      []nthWeekday
+}
+
+// nthWeekday is a BYDAY entry: n is 0 for every such weekday of the period,
+// 1 for the first and -1 for the last.
+type nthWeekday struct {
+	n   int
+	day time.Weekday
+}
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseRecurrence(value string, loc *time.Location) (*recurrence, error) {
+	r := &recurrence{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid part %q, expected NAME=value", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.freq = strings.ToUpper(val)
+			if r.freq != "DAILY" && r.freq != "WEEKLY" && r.freq != "MONTHLY" && r.freq != "YEARLY" {
+				return nil, fmt.Errorf("unsupported FREQ %q, expected DAILY, WEEKLY, MONTHLY or YEARLY", val)
+			}
+		case "INTERVAL":
+			if r.interval, err = strconv.Atoi(val); err != nil || r.interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+		case "COUNT":
+			if r.count, err = strconv.Atoi(val); err != nil || r.count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+		case "UNTIL":
+			until, untilLocation, allDay, err := parseICSTime(icsProperty{value: val}, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL: %w", err)
+			}
+			// UNTIL is inclusive: a date covers its whole day
+			if allDay {
+				r.until = WallClock(until.Year(), until.Month(), until.Day()+1, 0, untilLocation)
+			} else {
+				r.until = WallClock(until.Year(), until.Month(), until.Day(), clock(until), untilLocation).Add(time.Minute)
+			}
+		case "BYMONTH":
+			for _, v := range strings.Split(val, ",") {
+				month, err := strconv.Atoi(v)
+				if err != nil || month < 1 || month > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH %q", v)
+				}
+				r.byMonth = append(r.byMonth, time.Month(month))
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(val, ",") {
+				day, err := strconv.Atoi(v)
+				if err != nil || day == 0 || day < -31 || day > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", v)
+				}
+				r.byMonthDay = append(r.byMonthDay, day)
+			}
+		case "BYDAY":
+			for _, v := range strings.Split(val, ",") {
+				v = strings.ToUpper(v)
+				if len(v) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				day, ok := icsWeekdays[v[len(v)-2:]]
+				n := 0
+				if prefix := v[:len(v)-2]; prefix != "" {
+					if n, err = strconv.Atoi(prefix); err != nil || n == 0 || n < -53 || n > 53 {
+						ok = false
+					}
+				}
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				r.byDay = append(r.byDay, nthWeekday{n: n, day: day})
+			}
+		case "WKST":
+			// Weeks start on Monday, the RFC 5545 default
+		default:
+			return nil, fmt.Errorf("unsupported part %q", key)
+		}
+	}
+	if r.freq == "" {
+		return nil, fmt.Errorf("missing FREQ")
+	}
+	if r.count > 0 && !r.until.IsZero() {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot be combined")
+	}
+	for _, d := range r.byDay {
+		if d.n != 0 && r.freq != "MONTHLY" && r.freq != "YEARLY" {
+			return nil, fmt.Errorf("numbered BYDAY requires FREQ=MONTHLY or FREQ=YEARLY")
+		}
+	}
+	return r, nil
+}
+
+// each calls yield with the starts of the occurrences of r for an event
+// starting at dtstart, all UTC wall-clock times, in order. Occurrences
+// before the day of from may be skipped, and none are looked for after
+// limit. It stops when yield returns false.
+func (r *recurrence) each(dtstart, from, limit time.Time, yield func(time.Time) bool) {
+	first := 0
+	if r.count == 0 && from.After(dtstart) {
+		// Without COUNT, the periods before from need not be expanded
+		first = max(0, r.periodIndex(dtstart, from)-1)
+	}
+	emitted := 0
+	for k := first; !r.periodStart(dtstart, k).After(limit); k++ {
+		days := r.candidates(dtstart, r.periodStart(dtstart, k))
+		if k == 0 {
+			// DTSTART is always the first occurrence
+			days = append(days, date(dtstart))
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+		for i, day := range days {
+			if i > 0 && day.Equal(days[i-1]) {
+				continue
+			}
+			at := day.Add(dtstart.Sub(date(dtstart)))
+			if at.Before(dtstart) {
+				continue
+			}
+			if r.count > 0 && emitted == r.count {
+				return
+			}
+			emitted++
+			if !yield(at) {
+				return
+			}
+		}
+	}
+}
+
+// periodStart returns the first day of the k-th period of r.
+func (r *recurrence) periodStart(dtstart time.Time, k int) time.Time {
+	day := date(dtstart)
+	switch r.freq {
+	case "DAILY":
+		return day.AddDate(0, 0, k*r.interval)
+	case "WEEKLY":
+		monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		return monday.AddDate(0, 0, 7*k*r.interval)
+	case "MONTHLY":
+		return time.Date(day.Year(), day.Month()+time.Month(k*r.interval), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(day.Year()+k*r.interval, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// periodIndex returns the index of the period of r holding the day of t.
+func (r *recurrence) periodIndex(dtstart, t time.Time) int {
+	from, to := date(dtstart), date(t)
+	switch r.freq {
+	case "DAILY":
+		return int(to.Sub(from).Hours()/24) / r.interval
+	case "WEEKLY":
+		return int(to.Sub(r.periodStart(dtstart, 0)).Hours()/24) / 7 / r.interval
+	case "MONTHLY":
+		return ((to.Year()-from.Year())*12 + int(to.Month()-from.Month())) / r.interval
+	default:
+		return (to.Year() - from.Year()) / r.interval
+	}
+}
+
+// candidates returns the days of the period starting at periodStart that
+// match r, unsorted.
+func (r *recurrence) candidates(dtstart, periodStart time.Time) []time.Time {
+	var days []time.Time
+	switch r.freq {
+	case "DAILY":
+		days = []time.Time{periodStart}
+	case "WEEKLY":
+		if len(r.byDay) == 0 {
+			return r.filter([]time.Time{periodStart.AddDate(0, 0, (int(dtstart.Weekday())+6)%7)})
+		}
+		for i := 0; i < 7; i++ {
+			days = append(days, periodStart.AddDate(0, 0, i))
+		}
+	case "MONTHLY":
+		return r.filter(r.monthDays(dtstart, periodStart))
+	default:
+		if len(r.byMonth) == 0 && len(r.byDay) > 0 && len(r.byMonthDay) == 0 {
+			// Numbered days count within the whole year
+			end := periodStart.AddDate(1, 0, 0)
+			return r.filter(matchByDay(r.byDay, periodStart, end))
+		}
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []time.Month{dtstart.Month()}
+		}
+		for _, month := range months {
+			first := time.Date(periodStart.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+			days = append(days, r.monthDays(dtstart, first)...)
+		}
+		return r.filter(days)
+	}
+	return r.filter(days)
+}
+
+// monthDays returns the days of the month starting at first selected by
+// BYMONTHDAY and BYDAY, or the day of the month of dtstart without either.
+func (r *recurrence) monthDays(dtstart, first time.Time) []time.Time {
+	end := first.AddDate(0, 1, 0)
+	length := int(end.Sub(first).Hours() / 24)
+	if len(r.byMonthDay) == 0 && len(r.byDay) == 0 {
+		if dtstart.Day() > length {
+			return nil
+		}
+		return []time.Time{first.AddDate(0, 0, dtstart.Day()-1)}
+	}
+	var days []time.Time
+	if len(r.byMonthDay) > 0 {
+		for _, d := range r.byMonthDay {
+			if d < 0 {
+				d += length + 1
+			}
+			if d >= 1 && d <= length {
+				days = append(days, first.AddDate(0, 0, d-1))
+			}
+		}
+		if len(r.byDay) == 0 {
+			return days
+		}
+		// BYDAY restricts BYMONTHDAY
+		byDay := matchByDay(r.byDay, first, end)
+		var both []time.Time
+		for _, day := range days {
+			for _, match := range byDay {
+				if day.Equal(match) {
+					both = append(both, day)
+					break
+				}
+			}
+		}
+		return both
+	}
+	return matchByDay(r.byDay, first, end)
+}
+
+// matchByDay returns the days in [from, to) matching byDay, numbered within
+// that range.
+func matchByDay(byDay []nthWeekday, from, to time.Time) []time.Time {
+	var days []time.Time
+	for _, d := range byDay {
+		var all []time.Time
+		for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+			if day.Weekday() == d.day {
+				all = append(all, day)
+			}
+		}
+		switch {
+		case d.n == 0:
+			days = append(days, all...)
+		case d.n > 0 && d.n <= len(all):
+			days = append(days, all[d.n-1])
+		case d.n < 0 && -d.n <= len(all):
+			days = append(days, all[len(all)+d.n])
+		}
+	}
+	return days
+}
+
+// filter keeps the days matching BYMONTH, BYMONTHDAY and unnumbered BYDAY.
+func (r *recurrence) filter(days []time.Time) []time.Time {
+	var kept []time.Time
+	for _, day := range days {
+		if len(r.byMonth) > 0 && !containsMonth(r.byMonth, day.Month()) {
+			continue
+		}
+		if r.freq == "DAILY" || r.freq == "WEEKLY" {
+			if len(r.byMonthDay) > 0 && !matchesMonthDay(r.byMonthDay, day) {
+				continue
+			}
+			if len(r.byDay) > 0 && !matchesWeekday(r.byDay, day.Weekday()) {
+				continue
+			}
+		}
+		kept = append(kept, day)
+	}
+	return kept
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesMonthDay(monthDays []int, day time.Time) bool {
+	length := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, d := range monthDays {
+		if d == day.Day() || d+length+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesWeekday(byDay []nthWeekday, weekday time.Weekday) bool {
+	for _, d := range byDay {
+		if d.day == weekday {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//HR//Closures//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:christmas\r\n" +
+	"SUMMARY:Christmas shutdown\r\n" +
+	"DTSTART;VALUE=DATE:20261224\r\n" +
+	"DTEND;VALUE=DATE:20270102\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:retro\r\n" +
+	"SUMMARY:Quarterly retro,\r\n" +
+	"  all hands\r\n" +
+	"DTSTART;TZID=Europe/Paris:20260105T140000\r\n" +
+	"DURATION:PT3H\r\n" +
+	"RRULE:FREQ=MONTHLY;INTERVAL=3;BYDAY=1MO\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"DURATION:PT5M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:friday\r\n" +
+	"SUMMARY:Friday afternoons\r\n" +
+	"DTSTART:20260102T150000\r\n" +
+	"DTEND:20260102T180000\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=FR;UNTIL=20260331T235959Z\r\n" +
+	"EXDATE:20260109T150000,20260116T150000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:friday\r\n" +
+	"RECURRENCE-ID:20260123T150000\r\n" +
+	"DTSTART:20260123T100000\r\n" +
+	"DTEND:20260123T120000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cancelled\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART;VALUE=DATE:20260601\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	s, err := ParseICS(testICS, berlin)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		time     string
+		expected bool
+	}{
+		// All-day events run from midnight to midnight in the calendar zone
+		{"2026-12-23T22:59:00Z", false},
+		{"2026-12-23T23:00:00Z", true},
+		{"2027-01-01T22:59:00Z", true},
+		{"2027-01-01T23:00:00Z", false},
+		// First Monday of every third month, 14:00-17:00 in Paris
+		{"2026-01-05T13:00:00Z", true},
+		{"2026-01-05T16:00:00Z", false},
+		{"2026-02-02T13:00:00Z", false},
+		{"2026-04-06T12:00:00Z", true}, // summer time
+		{"2026-04-06T15:00:00Z", false},
+		{"2031-01-06T13:00:00Z", true},
+		// Weekly on Friday until the end of March, with exceptions
+		{"2026-01-02T14:00:00Z", true},
+		{"2026-01-09T14:00:00Z", false},
+		{"2026-01-16T14:00:00Z", false},
+		{"2026-01-23T14:00:00Z", false}, // moved to the morning
+		{"2026-01-23T09:30:00Z", true},
+		{"2026-03-27T14:00:00Z", true},
+		{"2026-04-03T13:00:00Z", false},
+		// Cancelled events never apply
+		{"2026-06-01T10:00:00Z", false},
+	}
+	for _, test := range tests {
+		now, _ := time.Parse(time.RFC3339, test.time)
+		assert.Equal(t, test.expected, s.Active(now), "unexpected result at %s", test.time)
+	}
+
+	next, ok := s.NextTransition(time.Date(2026, 1, 26, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 1, 30, 14, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		dtstart  string
+		rule     string
+		expected []string
+	}{
+		{"2026-01-01", "FREQ=DAILY;COUNT=3", []string{"2026-01-01", "2026-01-02", "2026-01-03"}},
+		{"2026-01-01", "FREQ=DAILY;INTERVAL=10;COUNT=3", []string{"2026-01-01", "2026-01-11", "2026-01-21"}},
+		{"2026-01-05", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4", []string{"2026-01-05", "2026-01-07", "2026-01-12", "2026-01-14"}},
+		{"2026-01-05", "FREQ=WEEKLY;INTERVAL=2;COUNT=3", []string{"2026-01-05", "2026-01-19", "2026-02-02"}},
+		{"2026-01-30", "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3", []string{"2026-01-30", "2026-02-27", "2026-03-27"}},
+		{"2026-01-31", "FREQ=MONTHLY;COUNT=3", []string{"2026-01-31", "2026-03-31", "2026-05-31"}},
+		{"2026-01-31", "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3", []string{"2026-01-31", "2026-02-28", "2026-03-31"}},
+		{"2026-01-13", "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13;COUNT=2", []string{"2026-01-13", "2026-02-13"}},
+		{"2026-11-26", "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;COUNT=3", []string{"2026-11-26", "2027-11-25", "2028-11-23"}},
+		{"2026-07-14", "FREQ=YEARLY;UNTIL=20280714", []string{"2026-07-14", "2027-07-14", "2028-07-14"}},
+		{"2026-01-01", "FREQ=DAILY;BYMONTH=2;COUNT=2", []string{"2026-01-01", "2026-02-01"}},
+	}
+	for _, test := range tests {
+		rule, err := parseRecurrence(test.rule, time.UTC)
+		if !assert.NoError(t, err, "unexpected error for %s", test.rule) {
+			continue
+		}
+		dtstart, _ := time.Parse(time.DateOnly, test.dtstart)
+		var got []string
+		rule.each(dtstart, dtstart, dtstart.AddDate(5, 0, 0), func(at time.Time) bool {
+			if !rule.until.IsZero() && !at.Before(rule.until) {
+				return false
+			}
+			got = append(got, at.Format(time.DateOnly))
+			return len(got) < 10
+		})
+		assert.Equal(t, test.expected, got, "unexpected occurrences for %s", test.rule)
+	}
+}
+
+func TestParseICSErrors(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{"BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT", `event "x": missing DTSTART`},
+		{"BEGIN:VEVENT\nUID:a\nDTSTART;TZID=W. Europe Standard Time:20260101T090000\nEND:VEVENT",
+			`event "a": DTSTART: unknown timezone "W. Europe Standard Time", expected an IANA name such as "Europe/Paris"`},
+		{"BEGIN:VEVENT\nUID:a\nDTSTART:20260101\nRRULE:FREQ=HOURLY\nEND:VEVENT",
+			`event "a": RRULE: unsupported FREQ "HOURLY", expected DAILY, WEEKLY, MONTHLY or YEARLY`},
+		{"BEGIN:VEVENT\nUID:a\nDTSTART:20260101\nRRULE:FREQ=DAILY;BYSETPOS=1\nEND:VEVENT", `event "a": RRULE: unsupported part "BYSETPOS"`},
+		{"BEGIN:VEVENT\nUID:a\nDTSTART:20260101T100000\nDTEND:20260101T090000\nEND:VEVENT", `event "a": DTEND is not after DTSTART`},
+		{"BEGIN:VEVENT\nUID:a\nDTSTART:20260101\nDURATION:1H\nEND:VEVENT", `event "a": DURATION: invalid duration "1H", expected e.g. 90m, 1h30m, 1.5h, 2d or P1DT4H`},
+		{"BEGIN:VEVENT\nnonsense\nEND:VEVENT", `line 2: invalid content line "nonsense", expected NAME:value`},
+	}
+	for _, test := range tests {
+		_, err := ParseICS(test.data, time.UTC)
+		assert.EqualError(t, err, test.expected)
+	}
+}
@@ -0,0 +1,423 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The date rules restricting the days of a TimeRange:
+//
+//	dates   = [ months ] [ rule ]
+//	months  = mitem { "," mitem }
+//	mitem   = month [ "-" month ]
+//	month   = "Jan" | "January" | ... | "Q1" | ... | "Q4" (any case)
+//	rule    = [ "the" ] ordinal day "of" [ "each" | "the" ] period
+//	        | [ "the" ] ordinal unit "of" [ "each" | "the" ] period
+//	        | [ "the" ] ( "first" | "last" ) count units "of" [ "each" | "the" ] period
+//	ordinal = "first" | "second" | "third" | "fourth" | "fifth" | "last" | 1st | 2nd | ...
+//	unit    = "day" | "workday" | "working day"
+//	period  = "month" | "quarter" | "year"
+//
+// A quarter stands for its three months. Workdays are Monday to Friday;
+// public holidays are left to ScaleCalendar.
+
+// dateRule selects calendar dates, such as the last working days of each
+// month.
+type dateRule interface {
+	// matches reports whether the rule selects date, midnight UTC of a
+	// calendar day.
+	matches(date time.Time) bool
+}
+
+// allRules selects the dates every one of its rules selects.
+type allRules []dateRule
+
+func (r allRules) matches(date time.Time) bool {
+	for _, rule := range r {
+		if !rule.matches(date) {
+			return false
+		}
+	}
+	return true
+}
+
+// monthSet is a set of months, one bit per time.Month.
+type monthSet uint16
+
+func (s monthSet) matches(date time.Time) bool {
+	return s&(1<<uint(date.Month())) != 0
+}
+
+// monthRange returns the months from first to last, wrapping around
+// December when last comes before first.
+func monthRange(first, last time.Month) monthSet {
+	var s monthSet
+	for m := first; ; m = m%12 + 1 {
+		s |= 1 << uint(m)
+		if m == last {
+			return s
+		}
+	}
+}
+
+// period is the span a rule counts days in.
+type period int
+
+const (
+	periodMonth period = iota
+	periodQuarter
+	periodYear
+)
+
+// bounds returns the first day of the period holding date and the first day
+// of the next one.
+func (p period) bounds(date time.Time) (time.Time, time.Time) {
+	y, m := date.Year(), date.Month()
+	switch p {
+	case periodQuarter:
+		first := (m-1)/3*3 + 1
+		return time.Date(y, first, 1, 0, 0, 0, 0, time.UTC), time.Date(y, first+3, 1, 0, 0, 0, 0, time.UTC)
+	case periodYear:
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(y+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC), time.Date(y, m+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// periodWeekday is the nth given day of the week of each period, counted
+// from its end when fromEnd, such as the first Monday of the quarter.
+type periodWeekday struct {
+	n       int
+	fromEnd bool
+	day     time.Weekday
+	period  period
+}
+
+func (r *periodWeekday) matches(date time.Time) bool {
+	if date.Weekday() != r.day {
+		return false
+	}
+	start, end := r.period.bounds(date)
+	offset := daysBetween(start, date)
+	if r.fromEnd {
+		offset = daysBetween(date, end) - 1
+	}
+	return offset/7+1 == r.n
+}
+
+// periodDays are the days of each period from position first to last, both
+// included and counted from its end when fromEnd, such as the last three
+// working days of the month. Only workdays are counted when workdays is set.
+type periodDays struct {
+	first, last int
+	fromEnd     bool
+	workdays    bool
+	period      period
+}
+
+func (r *periodDays) matches(date time.Time) bool {
+	if r.workdays && !isWorkday(date.Weekday()) {
+		return false
+	}
+	start, end := r.period.bounds(date)
+	pos := r.count(start, date) + 1
+	if r.fromEnd {
+		pos = r.count(date, end)
+	}
+	return pos >= r.first && pos <= r.last
+}
+
+// count returns the number of counted days from from included to to
+// excluded.
+func (r *periodDays) count(from, to time.Time) int {
+	days := daysBetween(from, to)
+	if !r.workdays {
+		return days
+	}
+	// Any seven consecutive days hold five workdays
+	n := days / 7 * 5
+	for i := days / 7 * 7; i < days; i++ {
+		if isWorkday(from.AddDate(0, 0, i).Weekday()) {
+			n++
+		}
+	}
+	return n
+}
+
+func isWorkday(d time.Weekday) bool {
+	return d != time.Saturday && d != time.Sunday
+}
+
+// daysBetween returns the number of days from from to to, both midnight UTC.
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from) / (24 * time.Hour))
+}
+
+// maxOrdinal bounds the position of a day within a period.
+const maxOrdinal = 366
+
+var ordinalWords = map[string]int{"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5}
+
+// dates parses the optional months and rule that restrict the days of a
+// window, nil when there are none.
+func (p *scheduleParser) dates() (dateRule, error) {
+	var rules allRules
+	if p.atMonth() {
+		months, err := p.months()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, months)
+	}
+	if p.atOrdinal() {
+		rule, err := p.rule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	switch len(rules) {
+	case 0:
+		return nil, nil
+	case 1:
+		return rules[0], nil
+	}
+	return rules, nil
+}
+
+// atMonth reports whether the next token starts a month or a quarter.
+func (p *scheduleParser) atMonth() bool {
+	tok := p.lex.peek()
+	if tok.kind != tokenWord {
+		return false
+	}
+	if _, ok := parseMonth(tok.text); ok {
+		return true
+	}
+	return strings.EqualFold(tok.text, "Q") && adjacent(tok, p.lex.peekAt(1), tokenTime)
+}
+
+// atOrdinal reports whether the next tokens start an ordinal, optionally
+// after "the".
+func (p *scheduleParser) atOrdinal() bool {
+	n := 0
+	if tok := p.lex.peek(); tok.kind == tokenWord && strings.EqualFold(tok.text, "the") {
+		n++
+	}
+	tok := p.lex.peekAt(n)
+	switch tok.kind {
+	case tokenWord:
+		_, ok := ordinalWords[strings.ToLower(tok.text)]
+		return ok || strings.EqualFold(tok.text, "last")
+	case tokenTime:
+		suffix := p.lex.peekAt(n + 1)
+		return adjacent(tok, suffix, tokenWord) && isOrdinalSuffix(suffix.text)
+	}
+	return false
+}
+
+// months parses a list of months, quarters and ranges of them.
+func (p *scheduleParser) months() (monthSet, error) {
+	var months monthSet
+	for {
+		first, last, err := p.month()
+		if err != nil {
+			return 0, err
+		}
+		if p.lex.peek().kind == tokenDash {
+			p.lex.next()
+			if _, last, err = p.month(); err != nil {
+				return 0, err
+			}
+		}
+		months |= monthRange(first, last)
+
+		if p.lex.peek().kind != tokenComma {
+			return months, nil
+		}
+		p.lex.next()
+	}
+}
+
+// month parses a month or a quarter and returns its first and last months.
+func (p *scheduleParser) month() (time.Month, time.Month, error) {
+	const expected = "a month, Jan to Dec, or a quarter, Q1 to Q4"
+	tok := p.lex.next()
+	if tok.kind != tokenWord {
+		return 0, 0, unexpected(tok, expected)
+	}
+	if m, ok := parseMonth(tok.text); ok {
+		return m, m, nil
+	}
+	if number := p.lex.peek(); strings.EqualFold(tok.text, "Q") && adjacent(tok, number, tokenTime) {
+		p.lex.next()
+		q, err := strconv.Atoi(number.text)
+		if err != nil || q < 1 || q > 4 {
+			return 0, 0, &SyntaxError{Column: tok.column, Found: tok.text + number.text,
+				Expected: "Q1, Q2, Q3 or Q4", Problem: "unknown quarter"}
+		}
+		return time.Month(3*q - 2), time.Month(3 * q), nil
+	}
+	return 0, 0, &SyntaxError{Column: tok.column, Found: tok.text, Expected: expected, Problem: "unknown month"}
+}
+
+// rule parses an nth day of the week, or a position or range of days, in a
+// period.
+func (p *scheduleParser) rule() (dateRule, error) {
+	if tok := p.lex.peek(); tok.kind == tokenWord && strings.EqualFold(tok.text, "the") {
+		p.lex.next()
+	}
+	n, fromEnd, err := p.ordinal()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.lex.peek(); tok.kind == tokenWord {
+		if day, ok := parseWeekday(tok.text); ok {
+			p.lex.next()
+			if n > 5 {
+				return nil, &SyntaxError{Column: tok.column, Found: tok.text,
+					Expected: "an ordinal from first to fifth", Problem: "no such day"}
+			}
+			period, err := p.period()
+			if err != nil {
+				return nil, err
+			}
+			return &periodWeekday{n: n, fromEnd: fromEnd, day: day, period: period}, nil
+		}
+	}
+
+	rule := &periodDays{first: n, last: n, fromEnd: fromEnd}
+	if count := p.lex.peek(); count.kind == tokenTime {
+		// "first 3 days", "last 2 workdays": a count of days after first or last
+		if n != 1 {
+			return nil, unexpected(count, `"days" or "workdays" after an ordinal other than first or last`)
+		}
+		p.lex.next()
+		c, err := strconv.Atoi(count.text)
+		if err != nil || c < 1 || c > maxOrdinal {
+			return nil, &SyntaxError{Column: count.column, Found: count.text,
+				Expected: "a number of days from 1 to 366", Problem: "invalid count"}
+		}
+		rule.first, rule.last = 1, c
+	}
+	if rule.workdays, err = p.unit(); err != nil {
+		return nil, err
+	}
+	if rule.period, err = p.period(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// ordinal parses "first" to "fifth", "last" or a number with an ordinal
+// suffix such as "2nd".
+func (p *scheduleParser) ordinal() (int, bool, error) {
+	tok := p.lex.next()
+	if tok.kind == tokenWord {
+		if strings.EqualFold(tok.text, "last") {
+			return 1, true, nil
+		}
+		return ordinalWords[strings.ToLower(tok.text)], false, nil
+	}
+	p.lex.next() // the suffix
+	n, err := strconv.Atoi(tok.text)
+	if err != nil || n < 1 || n > maxOrdinal {
+		return 0, false, &SyntaxError{Column: tok.column, Found: tok.text,
+			Expected: "an ordinal from 1st to 366th", Problem: "invalid ordinal"}
+	}
+	return n, false, nil
+}
+
+// unit parses "day", "workday" or "working day", in the singular or the
+// plural, and reports whether only workdays count.
+func (p *scheduleParser) unit() (bool, error) {
+	const expected = `a day of the week, "days" or "workdays"`
+	tok := p.lex.next()
+	word := strings.ToLower(tok.text)
+	switch {
+	case tok.kind != tokenWord:
+		return false, unexpected(tok, expected)
+	case word == "day" || word == "days":
+		return false, nil
+	case word == "workday" || word == "workdays":
+		return true, nil
+	case word == "working":
+		if next := p.lex.next(); next.kind != tokenWord || !isDayUnit(next.text) {
+			return false, unexpected(next, `"days"`)
+		}
+		return true, nil
+	}
+	return false, &SyntaxError{Column: tok.column, Found: tok.text, Expected: expected, Problem: "unknown day"}
+}
+
+// period parses "of", optionally "each" or "the", and the period.
+func (p *scheduleParser) period() (period, error) {
+	if tok := p.lex.next(); tok.kind != tokenWord || !strings.EqualFold(tok.text, "of") {
+		return 0, unexpected(tok, `"of"`)
+	}
+	tok := p.lex.next()
+	if tok.kind == tokenWord && (strings.EqualFold(tok.text, "each") || strings.EqualFold(tok.text, "the")) {
+		tok = p.lex.next()
+	}
+	if tok.kind != tokenWord {
+		return 0, unexpected(tok, `"month", "quarter" or "year"`)
+	}
+	switch strings.ToLower(tok.text) {
+	case "month":
+		return periodMonth, nil
+	case "quarter":
+		return periodQuarter, nil
+	case "year":
+		return periodYear, nil
+	}
+	return 0, &SyntaxError{Column: tok.column, Found: tok.text,
+		Expected: `"month", "quarter" or "year"`, Problem: "unknown period"}
+}
+
+// adjacent reports whether next directly follows tok and is of kind, as the
+// digits of "Q1" or the suffix of "2nd".
+func adjacent(tok, next token, kind tokenKind) bool {
+	return next.kind == kind && next.column == tok.column+len([]rune(tok.text))
+}
+
+func isOrdinalSuffix(s string) bool {
+	switch strings.ToLower(s) {
+	case "st", "nd", "rd", "th":
+		return true
+	}
+	return false
+}
+
+func isDayUnit(s string) bool {
+	s = strings.ToLower(s)
+	return s == "day" || s == "days"
+}
+
+// parseMonth accepts abbreviated and full month names in any case.
+func parseMonth(s string) (time.Month, bool) {
+	s = strings.ToLower(s)
+	for m := time.January; m <= time.December; m++ {
+		name := strings.ToLower(m.String())
+		if s == name || s == name[:3] {
+			return m, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,145 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateRules(t *testing.T) {
+	tests := []struct {
+		input    string
+		dates    []string
+		expected []bool
+	}{
+		{
+			// June 2025 ends on Monday 30th
+			"last 3 workdays of month 00:00-23:59",
+			[]string{"2025-06-25", "2025-06-26", "2025-06-27", "2025-06-28", "2025-06-29", "2025-06-30"},
+			[]bool{false, true, true, false, false, true},
+		},
+		{
+			"last 3 working days of each month 00:00-23:59",
+			[]string{"2025-06-26", "2025-07-29", "2025-07-31"},
+			[]bool{true, true, true},
+		},
+		{
+			"the last 3 working days of each month 00:00-23:59",
+			[]string{"2025-06-25", "2025-06-26", "2025-07-31"},
+			[]bool{false, true, true},
+		},
+		{
+			"the first Monday of the quarter 00:00-23:59",
+			[]string{"2025-04-07", "2025-04-14", "2025-07-07"},
+			[]bool{true, false, true},
+		},
+		{
+			"first Mon of quarter 00:00-23:59",
+			[]string{"2025-04-07", "2025-04-14", "2025-05-05", "2025-07-07", "2025-10-06"},
+			[]bool{true, false, false, true, true},
+		},
+		{
+			"last Fri of month 00:00-23:59",
+			[]string{"2025-06-27", "2025-06-20", "2025-02-28"},
+			[]bool{true, false, true},
+		},
+		{
+			"2nd Tue of month 00:00-23:59",
+			[]string{"2025-06-03", "2025-06-10", "2025-07-08"},
+			[]bool{false, true, true},
+		},
+		{
+			"last 5 days of quarter 00:00-23:59",
+			[]string{"2025-03-26", "2025-03-27", "2025-03-31", "2025-04-01", "2025-12-29"},
+			[]bool{false, true, true, false, true},
+		},
+		{
+			"first workday of year 00:00-23:59",
+			[]string{"2022-01-01", "2022-01-03", "2022-01-04"},
+			[]bool{false, true, false},
+		},
+		{
+			"15th day of month 00:00-23:59",
+			[]string{"2025-06-14", "2025-06-15", "2025-07-15"},
+			[]bool{false, true, true},
+		},
+		{
+			"Jan-Mar Mon-Fri 00:00-23:59",
+			[]string{"2025-01-06", "2025-03-31", "2025-04-01", "2025-01-04"},
+			[]bool{true, true, false, false},
+		},
+		{
+			"Nov-Feb 00:00-23:59",
+			[]string{"2025-11-01", "2026-02-28", "2026-03-01"},
+			[]bool{true, true, false},
+		},
+		{
+			"Q4 last 2 workdays of month 00:00-23:59",
+			[]string{"2025-10-30", "2025-10-31", "2025-09-30", "2025-12-31"},
+			[]bool{true, true, false, true},
+		},
+		{
+			"Q1,Q3 00:00-23:59",
+			[]string{"2025-02-01", "2025-05-01", "2025-08-01", "2025-11-01"},
+			[]bool{true, false, true, false},
+		},
+		{
+			"Q2-Q3 00:00-23:59",
+			[]string{"2025-03-31", "2025-04-01", "2025-09-30", "2025-10-01"},
+			[]bool{false, true, true, false},
+		},
+	}
+
+	for _, test := range tests {
+		s, err := Parse(test.input, nil, nil)
+		if !assert.NoError(t, err, "did not expect an error for input: %s", test.input) {
+			continue
+		}
+		for i, date := range test.dates {
+			at, _ := time.Parse("2006-01-02 15:04", date+" 12:00")
+			assert.Equal(t, test.expected[i], s.Active(at), "unexpected result for input: %s at %s", test.input, date)
+		}
+	}
+}
+
+func TestDateRulesLocation(t *testing.T) {
+	// The dates are those of the window's timezone: 30 June 23:00 in Tokyo
+	// is still 30 June 16:00 in Paris
+	w, err := ParseWindow("last day of month 00:00-23:59 Europe/Paris", nil)
+	assert.NoError(t, err)
+	assert.True(t, w.Active(time.Date(2025, 6, 30, 14, 0, 0, 0, time.UTC)))
+	assert.False(t, w.Active(time.Date(2025, 6, 30, 22, 30, 0, 0, time.UTC)))
+
+	s, err := Parse("last 3 workdays of month 08:00-18:00", nil, nil)
+	assert.NoError(t, err)
+	next, ok := s.NextTransition(time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC))
+	if assert.True(t, ok) {
+		assert.Equal(t, time.Date(2025, 6, 26, 8, 0, 0, 0, time.UTC), next)
+	}
+}
+
+func TestParseDateRulesErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		errorMsg string
+	}{
+		{"Jan-Foo 08:00-18:00", `column 5: unknown month "Foo", expected a month, Jan to Dec, or a quarter, Q1 to Q4`},
+		{"Q5 08:00-18:00", `column 1: unknown quarter "Q5", expected Q1, Q2, Q3 or Q4`},
+		{"first Mon of week 08:00-18:00", `column 14: unknown period "week", expected "month", "quarter" or "year"`},
+		{"sixth Mon of month 08:00-18:00", `column 1: unknown day "sixth", expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun`},
+		{"6th Mon of month 08:00-18:00", `column 5: no such day "Mon", expected an ordinal from first to fifth`},
+		{"last 3 weeks of month 08:00-18:00", `column 8: unknown day "weeks", expected a day of the week, "days" or "workdays"`},
+		{"second 3 days of month 08:00-18:00", `column 8: expected "days" or "workdays" after an ordinal other than first or last, found "3"`},
+		{"last 0 days of month 08:00-18:00", `column 6: invalid count "0", expected a number of days from 1 to 366`},
+		{"last working hours of month 08:00-18:00", `column 14: expected "days", found "hours"`},
+		{"last day month 08:00-18:00", `column 10: expected "of", found "month"`},
+		{"the Monday of month 08:00-18:00", `column 1: unknown day "the", expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun`},
+		{"last Fri of month 20:00-Mon 07:00", `column 25: expected a time as H:MM, found "Mon"`},
+	}
+
+	for _, test := range tests {
+		_, err := ParseWindow(test.input, nil)
+		assert.EqualError(t, err, test.errorMsg, "unexpected error for input: %s", test.input)
+	}
+}
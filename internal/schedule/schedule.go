@@ -0,0 +1,247 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule parses the kubescale uptime and downtime syntax and
+// evaluates it: whether a schedule is active at a given time, when it next
+// changes and which intervals it covers.
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// PresetPrefix marks a window as a reference to a named preset.
+const PresetPrefix = "@"
+
+// MaxLookahead bounds how far ahead NextTransition searches.
+const MaxLookahead = 366 * 24 * time.Hour
+
+var presetNameRegex = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// Window is one period of a schedule.
+type Window interface {
+	// Active reports whether t falls in the window.
+	Active(t time.Time) bool
+	// NextTransition returns the first instant after t at which Active may
+	// change, and false when it never does.
+	NextTransition(t time.Time) (time.Time, bool)
+}
+
+// Presets resolves preset references to the windows they stand for.
+type Presets interface {
+	Lookup(name string) ([]string, bool)
+}
+
+// Schedule is a parsed uptime or downtime value: a union of windows. A nil
+// Schedule is never active.
+type Schedule struct {
+	windows []Window
+}
+
+// Interval is a period during which a schedule is active.
+type Interval struct {
+	Start, End time.Time
+}
+
+// Parse parses an uptime or downtime value, expanding preset references
+// with presets, which may be nil. Windows without a timezone are read in
+// loc, UTC when nil. A malformed window fails the whole value.
+func Parse(value string, presets Presets, loc *time.Location) (*Schedule, error) {
+	entries := Split(value)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("invalid format %q, expected \"[Day-Day] HH:MM-HH:MM [Timezone]\"", value)
+	}
+
+	s := &Schedule{}
+	for i, entry := range entries {
+		windows := []string{entry}
+		if name, ok := strings.CutPrefix(entry, PresetPrefix); ok {
+			if presets == nil {
+				ok = false
+			} else {
+				windows, ok = presets.Lookup(name)
+			}
+			if !ok {
+				return nil, windowError(entries, i, fmt.Errorf("unknown preset %q", name))
+			}
+		}
+		for _, text := range windows {
+			w, err := ParseWindow(text, loc)
+			if err != nil {
+				return nil, windowError(entries, i, err)
+			}
+			s.windows = append(s.windows, w)
+		}
+	}
+	return s, nil
+}
+
+// Validate checks every window of value without resolving preset
+// references, whose existence is only known at evaluation time: a
+// reference is valid when its name is well formed.
+func Validate(value string) error {
+	entries := Split(value)
+	if len(entries) == 0 {
+		return fmt.Errorf("invalid format %q, expected \"[Day-Day] HH:MM-HH:MM [Timezone]\"", value)
+	}
+	for i, entry := range entries {
+		var err error
+		if name, ok := strings.CutPrefix(entry, PresetPrefix); ok {
+			if !presetNameRegex.MatchString(name) {
+				err = fmt.Errorf("invalid preset name %q", name)
+			}
+		} else {
+			_, err = ParseWindow(entry, nil)
+		}
+		if err != nil {
+			return windowError(entries, i, err)
+		}
+	}
+	return nil
+}
+
+// ParseWindow parses a single window: a cron pair, a dated window or a
+// day/time window. Without a timezone it is read in loc, UTC when nil.
+func ParseWindow(value string, loc *time.Location) (Window, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if isCronWindow(value) {
+		return parseCronWindow(value, loc)
+	}
+	if isDatedWindow(value) {
+		return parseDatedWindow(value, loc)
+	}
+	return parseTimeRange(value, loc)
+}
+
+// Active reports whether t falls in one of the windows of s.
+func (s *Schedule) Active(t time.Time) bool {
+	if s == nil {
+		return false
+	}
+	for _, w := range s.windows {
+		if w.Active(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextTransition returns the first instant after t at which s turns active
+// or inactive, and false when that does not happen within MaxLookahead.
+func (s *Schedule) NextTransition(t time.Time) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	active := s.Active(t)
+	for at := t; at.Sub(t) <= MaxLookahead; {
+		next, ok := s.nextChange(at)
+		if !ok || next.Sub(t) > MaxLookahead {
+			return time.Time{}, false
+		}
+		if s.Active(next) != active {
+			return next, true
+		}
+		at = next
+	}
+	return time.Time{}, false
+}
+
+// nextChange returns the first instant after t at which any window may
+// change.
+func (s *Schedule) nextChange(t time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, w := range s.windows {
+		if at, ok := w.NextTransition(t); ok && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// Windows returns the intervals during which s is active between from and
+// to, clipped to them.
+func (s *Schedule) Windows(from, to time.Time) []Interval {
+	var intervals []Interval
+	active := s.Active(from)
+	start := from
+	for start.Before(to) {
+		next, ok := s.NextTransition(start)
+		if !ok || next.After(to) {
+			next = to
+		}
+		if active {
+			intervals = append(intervals, Interval{Start: start, End: next})
+		}
+		active = !active
+		start = next
+	}
+	return intervals
+}
+
+// Split splits an uptime or downtime value into its windows, which are
+// separated by commas or newlines. Commas inside parentheses belong to the
+// window, as in cron fields, and so do commas of a day or month list such
+// as "Mon,Wed,Fri 08:00-18:00" or "Q1,Q3 08:00-18:00". Empty entries are
+// ignored.
+func Split(value string) []string {
+	var windows []string
+	depth, start := 0, 0
+	for i, r := range value + "\n" {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case r == ',' && depth == 0 && isDayList(value[start:i]):
+			continue
+		case (r == ',' && depth == 0) || r == '\n':
+			if entry := strings.TrimSpace(value[start:min(i, len(value))]); entry != "" {
+				windows = append(windows, entry)
+			}
+			start = i + 1
+		}
+	}
+	return windows
+}
+
+// isDayList reports whether s only holds the months and days of a window so
+// far.
+func isDayList(s string) bool {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) && r != '-' && r != ',' {
+			return false
+		}
+	}
+	return true
+}
+
+// windowError names the offending window of a value holding several.
+func windowError(windows []string, i int, err error) error {
+	if len(windows) == 1 {
+		return err
+	}
+	return fmt.Errorf("window %d %q: %w", i+1, windows[i], err)
+}
@@ -0,0 +1,140 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	assert.Equal(t,
+		[]string{"cron(0 7 * * 1,3,5; 0 19 * * 1,3,5) Europe/Paris", "Sat-Sat 10:00-12:00"},
+		Split("cron(0 7 * * 1,3,5; 0 19 * * 1,3,5) Europe/Paris, Sat-Sat 10:00-12:00"))
+	assert.Equal(t, []string{"08:00-12:00", "13:00-18:00"}, Split("08:00-12:00,\n13:00-18:00\n"))
+	assert.Equal(t,
+		[]string{"Q1,Q3 Mon,Fri 08:00-18:00", "last 3 workdays of month 08:00-20:00"},
+		Split("Q1,Q3 Mon,Fri 08:00-18:00, last 3 workdays of month 08:00-20:00"))
+	assert.Empty(t, Split(" , "))
+}
+
+type presetMap map[string][]string
+
+func (p presetMap) Lookup(name string) ([]string, bool) {
+	windows, ok := p[name]
+	return windows, ok
+}
+
+func TestParse(t *testing.T) {
+	presets := presetMap{"office-hours": {"Mon-Fri 08:00-12:00", "Mon-Fri 13:00-18:00"}}
+	monday := func(hour, min int) time.Time { return time.Date(2025, 6, 2, hour, min, 0, 0, time.UTC) }
+
+	tests := []struct {
+		value    string
+		at       time.Time
+		expected bool
+	}{
+		{"@office-hours", monday(9, 0), true},
+		{"@office-hours", monday(12, 30), false},
+		{"@office-hours, Mon-Fri 12:00-13:00", monday(12, 30), true},
+		{"Mon,Wed 08:00-10:00, Fri 20:00-Mon 07:00", monday(6, 0), true},
+		{"cron(0 12 * * *; 0 13 * * *), 2025-06-02 00:00 - 2025-06-02 01:00", monday(0, 30), true},
+	}
+	for _, test := range tests {
+		s, err := Parse(test.value, presets, nil)
+		if assert.NoError(t, err, "did not expect an error for %s", test.value) {
+			assert.Equal(t, test.expected, s.Active(test.at), "unexpected result for %s at %s", test.value, test.at)
+		}
+	}
+
+	_, err := Parse("@unknown", presets, nil)
+	assert.EqualError(t, err, `unknown preset "unknown"`)
+	_, err = Parse("@office-hours", nil, nil)
+	assert.EqualError(t, err, `unknown preset "office-hours"`)
+	_, err = Parse("Mon-Fri 08:00-12:00 UTC, weekends", nil, nil)
+	assert.EqualError(t, err, `window 2 "weekends": column 1: unknown day "weekends", expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun`)
+	_, err = Parse(" , ", nil, nil)
+	assert.EqualError(t, err, `invalid format " , ", expected "[Day-Day] HH:MM-HH:MM [Timezone]"`)
+
+	assert.False(t, (*Schedule)(nil).Active(monday(9, 0)))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("@office-hours, Mon-Fri 08:00-18:00"))
+	assert.EqualError(t, Validate("@office hours"), `invalid preset name "office hours"`)
+	assert.EqualError(t, Validate("Mon-Fri 08:00-18:00, cron(0 7 * * *)"),
+		`window 2 "cron(0 7 * * *)": invalid format "cron(0 7 * * *)", expected "cron(<start>; <stop>) [Timezone]"`)
+}
+
+func TestScheduleNextTransition(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	tests := []struct {
+		value    string
+		at       time.Time
+		expected time.Time
+	}{
+		// A TimeRange turns active at its start and inactive at its end
+		{"Mon-Fri 08:00-20:00", time.Date(2025, 6, 2, 7, 0, 30, 0, time.UTC), time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)},
+		{"Mon-Fri 08:00-20:00", time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC), time.Date(2025, 6, 2, 20, 0, 0, 0, time.UTC)},
+		// Friday evening goes to Monday morning
+		{"Mon-Fri 08:00-20:00", time.Date(2025, 6, 6, 21, 0, 0, 0, time.UTC), time.Date(2025, 6, 9, 8, 0, 0, 0, time.UTC)},
+		{"Mon-Fri 08:00-20:00 Europe/Paris", time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 2, 8, 0, 0, 0, paris)},
+		// Overlapping windows only change at the edges of their union
+		{"Mon-Fri 08:00-12:00, Mon-Fri 11:00-18:00", time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC), time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)},
+		{"Fri 20:00-Mon 07:00", time.Date(2025, 6, 7, 12, 0, 0, 0, time.UTC), time.Date(2025, 6, 9, 7, 0, 0, 0, time.UTC)},
+		{"cron(0 7 * * 1-5; 0 19 * * 1-5)", time.Date(2025, 6, 7, 12, 0, 0, 0, time.UTC), time.Date(2025, 6, 9, 7, 0, 0, 0, time.UTC)},
+		{"2026-12-23 18:00 - 2027-01-02 07:00", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 12, 23, 18, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		s, err := Parse(test.value, nil, nil)
+		if assert.NoError(t, err) {
+			next, ok := s.NextTransition(test.at)
+			assert.True(t, ok, "expected a transition for %s", test.value)
+			assert.True(t, test.expected.Equal(next), "unexpected transition for %s at %s: %s", test.value, test.at, next)
+		}
+	}
+
+	// An expired dated window never changes again
+	s, _ := Parse("2024-12-23 18:00 - 2025-01-02 07:00", nil, nil)
+	_, ok := s.NextTransition(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
+
+func TestScheduleWindows(t *testing.T) {
+	s, err := Parse("Mon-Fri 08:00-12:00, Wed 14:00-Thu 09:00", nil, nil)
+	assert.NoError(t, err)
+
+	day := func(d, hour, min int) time.Time { return time.Date(2025, 6, d, hour, min, 0, 0, time.UTC) }
+	assert.Equal(t, []Interval{
+		{Start: day(2, 9, 0), End: day(2, 12, 0)},
+		{Start: day(3, 8, 0), End: day(3, 12, 0)},
+		{Start: day(4, 8, 0), End: day(4, 12, 0)},
+		{Start: day(4, 14, 0), End: day(5, 12, 0)},
+	}, s.Windows(day(2, 9, 0), day(5, 13, 0)))
+
+	assert.Empty(t, (*Schedule)(nil).Windows(day(2, 0, 0), day(9, 0, 0)))
+}
+
+func TestParseDefaultLocation(t *testing.T) {
+	singapore, _ := time.LoadLocation("Asia/Singapore")
+	at := time.Date(2025, 6, 2, 1, 0, 0, 0, time.UTC) // Monday 09:00 in Singapore
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{"Mon-Fri 08:00-18:00", true},
+		{"Mon-Fri 08:00-18:00 UTC", false},
+		{"Mon 08:00-Tue 08:00", true},
+		{"cron(0 8 * * 1-5; 0 18 * * 1-5)", true},
+		{"2025-06-02 08:00 - 2025-06-02 10:00", true},
+		{"2025-06-02 08:00 - 2025-06-02 10:00 UTC", false},
+	}
+	for _, test := range tests {
+		s, err := Parse(test.value, nil, singapore)
+		if assert.NoError(t, err) {
+			assert.Equal(t, test.expected, s.Active(at), "unexpected result for %s", test.value)
+		}
+	}
+	assert.Equal(t, []string{"2025-06-02 08:00 - 2025-06-02 08:30"}, Expired("2025-06-02 08:00 - 2025-06-02 08:30", at, singapore))
+	assert.Empty(t, Expired("2025-06-02 08:00 - 2025-06-02 08:30", at, nil))
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"time"
+)
+
+// TimeRange is the same hours on each of a set of days, such as
+// "Mon-Fri 08:00-20:00". It is active from Start included to End excluded.
+// When End is not after Start the hours wrap around midnight within each
+// day. Times are wall-clock times in Location, resolved with WallClock.
+// Dates, when set, further restricts the days, as in
+// "last 3 workdays of month 08:00-20:00".
+type TimeRange struct {
+	Days     weekdaySet
+	Dates    dateRule
+	Start    time.Time
+	End      time.Time
+	Location *time.Location
+}
+
+// Active implements Window.
+func (tr *TimeRange) Active(t time.Time) bool {
+	local := t.In(tr.Location)
+	if !tr.Days.has(local.Weekday()) {
+		return false
+	}
+	y, m, d := local.Date()
+	if tr.Dates != nil && !tr.Dates.matches(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)) {
+		return false
+	}
+	start := WallClock(y, m, d, clock(tr.Start), tr.Location)
+	end := WallClock(y, m, d, clock(tr.End), tr.Location)
+	if clock(tr.Start) < clock(tr.End) {
+		return !t.Before(start) && t.Before(end)
+	}
+	return !t.Before(start) || t.Before(end)
+}
+
+// NextTransition implements Window. A range changes at midnight, when the
+// day changes, at Start and at End.
+func (tr *TimeRange) NextTransition(t time.Time) (time.Time, bool) {
+	return nextWallClock(t, tr.Location, 0, clock(tr.Start), clock(tr.End))
+}
+
+// weekSpan is a weekly interval from a day and time to another, such as
+// Friday evening to Monday morning. Unlike a TimeRange it is one continuous
+// interval rather than the same hours on each day.
+type weekSpan struct {
+	startDay, endDay time.Weekday
+	start, end       time.Time
+	location         *time.Location
+}
+
+// length returns the number of days from the start day to the end day.
+func (w *weekSpan) length() int {
+	days := int(w.endDay-w.startDay+7) % 7
+	if days == 0 && clock(w.end) <= clock(w.start) {
+		days = 7
+	}
+	return days
+}
+
+// Active reports whether t falls between the latest start at or before t
+// and the end that follows it.
+func (w *weekSpan) Active(t time.Time) bool {
+	y, m, d := t.In(w.location).Date()
+	for i := 0; i <= 7; i++ {
+		day := time.Date(y, m, d-i, 0, 0, 0, 0, time.UTC)
+		if day.Weekday() != w.startDay {
+			continue
+		}
+		start := WallClock(day.Year(), day.Month(), day.Day(), clock(w.start), w.location)
+		if start.After(t) {
+			continue
+		}
+		end := WallClock(day.Year(), day.Month(), day.Day()+w.length(), clock(w.end), w.location)
+		return t.Before(end)
+	}
+	return false
+}
+
+func (w *weekSpan) NextTransition(t time.Time) (time.Time, bool) {
+	return nextWallClock(t, w.location, clock(w.start), clock(w.end))
+}
+
+// clock returns the minutes since midnight of t.
+func clock(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+// WallClock returns the instant at which the clock in loc shows minute,
+// counted from midnight, on the given day. A time skipped when clocks move
+// forward resolves to the end of the gap, and a time repeated when they move
+// back to its first occurrence, so every wall-clock time maps to a single
+// instant and later times never map to earlier instants.
+func WallClock(year int, month time.Month, day, minute int, loc *time.Location) time.Time {
+	want := time.Date(year, month, day, 0, minute, 0, 0, time.UTC)
+	t := time.Date(year, month, day, 0, minute, 0, 0, loc)
+	if got := wall(t); !got.Equal(want) {
+		// Skipped: t landed on one side of the gap
+		start, end := t.ZoneBounds()
+		if got.After(want) {
+			return start
+		}
+		return end
+	}
+	// Repeated: the same clock under the previous offset comes first
+	if start, _ := t.ZoneBounds(); !start.IsZero() {
+		_, offset := start.Add(-time.Second).Zone()
+		earlier := want.Add(-time.Duration(offset) * time.Second)
+		if earlier.Before(start) && wall(earlier.In(loc)).Equal(want) {
+			return earlier.In(loc)
+		}
+	}
+	return t
+}
+
+// wall returns the clock reading of t as a UTC time.
+func wall(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// nextWallClock returns the first instant after t whose clock in loc shows
+// one of clocks, given as minutes since midnight, within the next week.
+func nextWallClock(t time.Time, loc *time.Location, clocks ...int) (time.Time, bool) {
+	y, m, d := t.In(loc).Date()
+	for i := 0; i <= 7; i++ {
+		var next time.Time
+		for _, c := range clocks {
+			at := WallClock(y, m, d+i, c, loc)
+			if at.After(t) && (next.IsZero() || at.Before(next)) {
+				next = at
+			}
+		}
+		if !next.IsZero() {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseHourMin(s string) (time.Time, error) {
+	return time.Parse("15:04", s)
+}
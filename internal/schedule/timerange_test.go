@@ -0,0 +1,167 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	tests := []struct {
+		input       string
+		days        weekdaySet
+		startTime   string
+		endTime     string
+		location    string
+		expectError bool
+	}{
+		{"Mon-Fri 08:00-20:00 UTC", weekdayRange(time.Monday, time.Friday), "08:00", "20:00", "UTC", false},
+		{"Sat-Sun 22:00-06:00 UTC", weekdayRange(time.Saturday, time.Sunday), "22:00", "06:00", "UTC", false},
+		{"Mon-Fri 08:00-20:00 InvalidTZ", 0, "", "", "", true},
+		{"InvalidFormat", 0, "", "", "", true},
+		{"Mon-Fri InvalidTimeRange UTC", 0, "", "", "", true},
+		{"InvalidDayRange 08:00-20:00 UTC", 0, "", "", "", true},
+		{"Mon-Fri 08:00-20:00 UTC trailing", 0, "", "", "", true},
+		{"08:00-20:00 UTC", everyDay, "08:00", "20:00", "UTC", false},                                   // Default days
+		{"Mon-Fri 08:00-20:00", weekdayRange(time.Monday, time.Friday), "08:00", "20:00", "UTC", false}, // Default timezone
+		{"Mon,Wed,Fri 08:00-20:00", 1<<time.Monday | 1<<time.Wednesday | 1<<time.Friday, "08:00", "20:00", "UTC", false},
+		{"Mon-Wed, Fri 8:00-20:00", weekdayRange(time.Monday, time.Wednesday) | 1<<time.Friday, "08:00", "20:00", "UTC", false},
+		{"monday-FRIDAY 8:00-9:30 Europe/Paris", weekdayRange(time.Monday, time.Friday), "08:00", "09:30", "Europe/Paris", false},
+		{"Fri-Mon 20:00-07:00", weekdayRange(time.Friday, time.Monday), "20:00", "07:00", "UTC", false},
+	}
+
+	for _, test := range tests {
+		w, err := parseTimeRange(test.input, time.UTC)
+		if test.expectError {
+			assert.Error(t, err, "expected an error for input: %s", test.input)
+		} else if assert.NoError(t, err, "did not expect an error for input: %s", test.input) {
+			timerange := w.(*TimeRange)
+			assert.Equal(t, test.days, timerange.Days, "unexpected days for input: %s", test.input)
+			assert.Equal(t, test.startTime, timerange.Start.Format("15:04"), "unexpected start time for input: %s", test.input)
+			assert.Equal(t, test.endTime, timerange.End.Format("15:04"), "unexpected end time for input: %s", test.input)
+			assert.Equal(t, test.location, timerange.Location.String(), "unexpected location for input: %s", test.input)
+		}
+	}
+}
+
+func TestParseTimeRangeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		errorMsg string
+	}{
+		{"Mon-Fir 08:00-18:00", `column 5: unknown day "Fir", expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun`},
+		{"Mon-Fri 08:00-25:00", `column 15: invalid time "25:00", expected H:MM`},
+		{"Mon-Fri 08:00 18:00", `column 15: expected "-", found "18:00"`},
+		{"Mon-Fri", `column 8: expected a time as H:MM, found end of input`},
+		{"Mon,,Fri 08:00-18:00", `column 5: expected a day, one of Mon, Tue, Wed, Thu, Fri, Sat, Sun, found ","`},
+		{"08:00-18:00 Europe/Pari", `column 13: unknown timezone "Europe/Pari", expected an IANA name such as "Europe/Paris"`},
+		{"08:00-18:00 UTC now", `column 17: expected end of input, found "now"`},
+		{"Mon-Fri 20:00-Mon 07:00", `column 1: invalid start "Mon", expected a single start day when the end has a day`},
+		{"Fri 20:00-Mon 07:61", `column 15: invalid time "07:61", expected H:MM`},
+		{"*08:00-18:00", `column 1: expected a day or a time, found "*"`},
+	}
+
+	for _, test := range tests {
+		_, err := parseTimeRange(test.input, time.UTC)
+		assert.EqualError(t, err, test.errorMsg, "unexpected error for input: %s", test.input)
+	}
+}
+
+func TestWeekSpan(t *testing.T) {
+	tests := []struct {
+		input       string
+		currentTime string
+		expected    bool
+	}{
+		{"Fri 20:00-Mon 07:00", "2025-06-06T19:00:00Z", false}, // Friday before the start
+		{"Fri 20:00-Mon 07:00", "2025-06-06T21:00:00Z", true},  // Friday evening
+		{"Fri 20:00-Mon 07:00", "2025-06-07T14:00:00Z", true},  // Saturday afternoon
+		{"Fri 20:00-Mon 07:00", "2025-06-09T06:00:00Z", true},  // Monday morning
+		{"Fri 20:00-Mon 07:00", "2025-06-09T08:00:00Z", false}, // Monday after the end
+		{"Fri 20:00-Mon 07:00", "2025-06-10T23:00:00Z", false}, // Tuesday night
+		{"Mon 08:00-Wed 12:00", "2025-06-03T03:00:00Z", true},  // Tuesday night
+		{"Mon 08:00-Wed 12:00", "2025-06-05T03:00:00Z", false}, // Thursday night
+		{"friday 20:00-monday 7:00 Europe/Paris", "2025-06-09T04:30:00Z", true},
+		{"friday 20:00-monday 7:00 Europe/Paris", "2025-06-09T05:30:00Z", false},
+	}
+
+	for _, test := range tests {
+		w, err := parseTimeRange(test.input, time.UTC)
+		if assert.NoError(t, err, "did not expect an error for input: %s", test.input) {
+			assert.IsType(t, &weekSpan{}, w)
+			current, _ := time.Parse(time.RFC3339, test.currentTime)
+			assert.Equal(t, test.expected, w.Active(current), "unexpected result for %s at %s", test.input, test.currentTime)
+		}
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    int
+		expectError bool
+	}{
+		{"Mon", 1, false},
+		{"Fri", 5, false},
+		{"Sun", 0, false},
+		{"sunday", 0, false},
+		{"WEDNESDAY", 3, false},
+		{"InvalidDay", 0, true},
+		{"Fir", 0, true},
+		{"Mo", 0, true},
+	}
+
+	for _, test := range tests {
+		result, ok := parseWeekday(test.input)
+		if test.expectError {
+			assert.False(t, ok, "expected an error for input: %s", test.input)
+		} else {
+			assert.True(t, ok, "did not expect an error for input: %s", test.input)
+			assert.Equal(t, time.Weekday(test.expected), result, "unexpected result for input: %s", test.input)
+		}
+	}
+}
+func TestTimeRangeActive(t *testing.T) {
+	tests := []struct {
+		startDay      time.Weekday
+		endDay        time.Weekday
+		startTime     string
+		endTime       string
+		location      string
+		currentTime   string
+		expectInRange bool
+	}{
+		{time.Monday, time.Friday, "08:00", "18:00", "UTC", "2023-10-03T10:00:00Z", true},        // Within range
+		{time.Monday, time.Friday, "08:00", "18:00", "UTC", "2023-10-02T19:00:00Z", false},       // Outside time range
+		{time.Monday, time.Friday, "18:00", "08:00", "UTC", "2023-10-02T19:00:00Z", true},        // Overnight range
+		{time.Monday, time.Friday, "08:00", "18:00", "UTC", "2023-10-07T09:00:00Z", false},       // Outside day range
+		{time.Monday, time.Friday, "08:00", "18:00", "UTC", "2023-10-02T08:00:00Z", true},        // Start is included
+		{time.Monday, time.Friday, "08:00", "18:00", "UTC", "2023-10-02T17:59:59Z", true},        // Up to the end
+		{time.Monday, time.Friday, "08:00", "18:00", "UTC", "2023-10-02T18:00:00Z", false},       // End is excluded
+		{time.Monday, time.Friday, "08:00", "18:00", "InvalidTZ", "2023-10-02T09:00:00Z", false}, // Invalid timezone
+	}
+
+	for _, test := range tests {
+		loc, err := time.LoadLocation(test.location)
+		if test.location == "InvalidTZ" {
+			assert.Error(t, err, "expected an error for invalid timezone")
+			continue
+		}
+		assert.NoError(t, err, "did not expect an error for valid timezone")
+
+		start, _ := time.Parse("15:04", test.startTime)
+		end, _ := time.Parse("15:04", test.endTime)
+		current, _ := time.Parse(time.RFC3339, test.currentTime)
+
+		tr := &TimeRange{
+			Days:     weekdayRange(test.startDay, test.endDay),
+			Start:    start,
+			End:      end,
+			Location: loc,
+		}
+		// Mock the Now function to return the current time
+		result := tr.Active(current)
+		assert.Equal(t, test.expectInRange, result, "unexpected result for test case: %+v", test)
+	}
+}
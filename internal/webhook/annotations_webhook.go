@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/cicd-toolkit/kubescale/internal/controller"
+	"github.com/cicd-toolkit/kubescale/internal/schedule"
+)
+
+// ValidateAnnotationsPath is where the annotation validating webhook is served.
+const ValidateAnnotationsPath = "/validate-kubescale-annotations"
+
+var annotationlog = logf.Log.WithName("annotation-webhook")
+
+// SetupAnnotationWebhookWithManager registers the annotation validating and
+// duration resolving webhooks on the manager's webhook server. loc is the
+// cluster default timezone of windows without one.
+func SetupAnnotationWebhookWithManager(mgr ctrl.Manager, loc *time.Location) {
+	mgr.GetWebhookServer().Register(ValidateAnnotationsPath, &webhook.Admission{
+		Handler: &AnnotationValidator{Reader: mgr.GetClient(), Location: loc},
+	})
+	mgr.GetWebhookServer().Register(MutateDurationsPath, &webhook.Admission{
+		Handler: &DurationResolver{Reader: mgr.GetClient(), Location: loc},
+	})
+}
+
+// namespaceLocation returns the timezone of windows without one for the
+// object of req: the kubescale/timezone of its namespace, read with reader
+// when set, or else loc. A Namespace object uses its own annotations.
+func namespaceLocation(
+	ctx context.Context,
+	reader client.Reader,
+	loc *time.Location,
+	req admission.Request,
+	annotations map[string]string,
+) *time.Location {
+	if req.Kind.Kind == "Namespace" {
+		return controller.NamespaceLocation(annotations, loc)
+	}
+	var ns corev1.Namespace
+	if reader == nil || req.Namespace == "" ||
+		reader.Get(ctx, client.ObjectKey{Name: req.Namespace}, &ns) != nil {
+		return controller.NamespaceLocation(nil, loc)
+	}
+	return controller.NamespaceLocation(ns.Annotations, loc)
+}
+
+// +kubebuilder:webhook:path=/validate-kubescale-annotations,mutating=false,failurePolicy=ignore,sideEffects=None,groups=apps;batch;"";monitoring.coreos.com,resources=deployments;statefulsets;daemonsets;cronjobs;namespaces;prometheuses,verbs=create;update,versions=v1,name=vannotations.kubescale.io,admissionReviewVersions=v1
+
+// AnnotationValidator rejects workloads and namespaces whose kubescale
+// annotations cannot be parsed, and warns about dated windows that have
+// already ended. Only the object metadata is decoded, so the same handler
+// serves every kind.
+type AnnotationValidator struct {
+	// Now returns the admission time. It defaults to time.Now.
+	Now func() time.Time
+	// Reader reads the namespace of the object. It may be nil.
+	Reader client.Reader
+	// Location is the timezone of windows without one, in namespaces
+	// without kubescale/timezone. It defaults to UTC.
+	Location *time.Location
+}
+
+// Handle implements admission.Handler.
+func (v *AnnotationValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var obj metav1.PartialObjectMetadata
+	if err := json.Unmarshal(req.Object.Raw, &obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// On update only the annotations that changed are checked, so an object
+	// stored before the webhook existed can still be updated, including by
+	// the controller itself.
+	var old metav1.PartialObjectMetadata
+	if len(req.OldObject.Raw) > 0 {
+		if err := json.Unmarshal(req.OldObject.Raw, &old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	changed := make(map[string]string)
+	for k, val := range obj.Annotations {
+		if prev, ok := old.Annotations[k]; !ok || prev != val {
+			changed[k] = val
+		}
+	}
+
+	if err := controller.ValidateAnnotations(changed); err != nil {
+		annotationlog.Info("Rejecting invalid annotations", "kind", req.Kind.Kind,
+			"namespace", req.Namespace, "name", req.Name, "error", err.Error())
+		return admission.Denied(err.Error())
+	}
+
+	now := time.Now
+	if v.Now != nil {
+		now = v.Now
+	}
+	var warnings []string
+	loc := namespaceLocation(ctx, v.Reader, v.Location, req, obj.Annotations)
+	for _, key := range []string{controller.UptimeAnnotation, controller.DowntimeAnnotation} {
+		for _, entry := range schedule.Expired(changed[key], now(), loc) {
+			warnings = append(warnings, fmt.Sprintf("%s: window %q has expired and can be removed", key, entry))
+		}
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func deployment(annotations map[string]string) runtime.RawExtension {
+	raw, _ := json.Marshal(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a", Annotations: annotations},
+	})
+	return runtime.RawExtension{Raw: raw}
+}
+
+func TestAnnotationValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		object  map[string]string
+		old     map[string]string
+		allowed bool
+	}{
+		{"valid uptime", map[string]string{"kubescale/uptime": "Mon-Fri 08:00-18:00 Europe/Paris"}, nil, true},
+		{"bad day", map[string]string{"kubescale/uptime": "Mon-Fir 8:00-18:00"}, nil, false},
+		{"unrelated annotations", map[string]string{"example.com/owner": "team-a"}, nil, true},
+		{
+			"unchanged invalid value on update",
+			map[string]string{"kubescale/uptime": "Mon-Fir 8:00-18:00", "kubescale/previous-replicas": "3"},
+			map[string]string{"kubescale/uptime": "Mon-Fir 8:00-18:00"},
+			true,
+		},
+		{
+			"changed invalid value on update",
+			map[string]string{"kubescale/up": "3 days"},
+			map[string]string{"kubescale/up": "3d"},
+			false,
+		},
+	}
+
+	v := &AnnotationValidator{}
+	for _, test := range tests {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    deployment(test.object),
+		}}
+		if test.old != nil {
+			req.Operation = admissionv1.Update
+			req.OldObject = deployment(test.old)
+		}
+		resp := v.Handle(context.Background(), req)
+		assert.Equal(t, test.allowed, resp.Allowed, "unexpected result for test case: %s", test.name)
+	}
+}
+
+func TestAnnotationValidatorExpiredWindows(t *testing.T) {
+	v := &AnnotationValidator{Now: func() time.Time { return time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC) }}
+	resp := v.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Object: deployment(map[string]string{
+			"kubescale/downtime": "Sat-Sun 00:00-23:59, 2025-12-23 18:00 - 2026-01-02 07:00 Europe/Berlin",
+		}),
+	}})
+	assert.True(t, resp.Allowed)
+	assert.Equal(t, []string{
+		`kubescale/downtime: window "2025-12-23 18:00 - 2026-01-02 07:00 Europe/Berlin" has expired and can be removed`,
+	}, resp.Warnings)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/cicd-toolkit/kubescale/internal/controller"
+)
+
+// MutateDurationsPath is where the duration resolving webhook is served.
+const MutateDurationsPath = "/mutate-kubescale-durations"
+
+// +kubebuilder:webhook:path=/mutate-kubescale-durations,mutating=true,failurePolicy=ignore,sideEffects=None,groups=apps;batch;monitoring.coreos.com,resources=deployments;statefulsets;daemonsets;cronjobs;prometheuses,verbs=create;update,versions=v1,name=mdurations.kubescale.io,admissionReviewVersions=v1
+
+// DurationResolver rewrites kubescale/up and kubescale/down into
+// kubescale/up-until and kubescale/down-until expiring after the time of the
+// request, so the object is stored already resolved and the controller has
+// nothing left to write.
+type DurationResolver struct {
+	// Now returns the admission time. It defaults to time.Now.
+	Now func() time.Time
+	// Reader reads the namespace of the object. It may be nil.
+	Reader client.Reader
+	// Location is the timezone expiries are written in, in namespaces
+	// without kubescale/timezone. It defaults to UTC.
+	Location *time.Location
+}
+
+// Handle implements admission.Handler.
+func (m *DurationResolver) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var obj unstructured.Unstructured
+	if err := json.Unmarshal(req.Object.Raw, &obj.Object); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	now := time.Now
+	if m.Now != nil {
+		now = m.Now
+	}
+
+	loc := namespaceLocation(ctx, m.Reader, m.Location, req, obj.GetAnnotations())
+	resolved, changed, err := controller.ResolveDurationAnnotations(obj.GetAnnotations(), now(), loc)
+	if err != nil {
+		// Leave the object alone, the validating webhook rejects it with the
+		// precise error.
+		return admission.Allowed("")
+	}
+	if !changed {
+		return admission.Allowed("")
+	}
+
+	obj.SetAnnotations(resolved)
+	raw, err := json.Marshal(obj.Object)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	annotationlog.Info("Resolved duration annotations", "kind", req.Kind.Kind,
+		"namespace", req.Namespace, "name", req.Name)
+	return admission.PatchResponseFromRaw(req.Object.Raw, raw)
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestDurationResolver(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC) // Monday
+	tests := []struct {
+		name    string
+		object  map[string]string
+		patches map[string]interface{}
+	}{
+		{
+			"up resolved",
+			map[string]string{"kubescale/up": "2h"},
+			map[string]interface{}{
+				"/metadata/annotations/kubescale~1up-until": "2025-06-02T12:00:00Z",
+				"/metadata/annotations/kubescale~1up":       nil,
+			},
+		},
+		{
+			"down resolved",
+			map[string]string{"kubescale/down": "30m", "kubescale/uptime": "Mon-Fri 08:00-18:00"},
+			map[string]interface{}{
+				"/metadata/annotations/kubescale~1down-until": "2025-06-02T10:30:00Z",
+				"/metadata/annotations/kubescale~1down":       nil,
+			},
+		},
+		{
+			"beyond a day, shown in local time",
+			map[string]string{"kubescale/up": "3d Europe/Paris"},
+			map[string]interface{}{
+				"/metadata/annotations/kubescale~1up-until": "2025-06-05T12:00:00+02:00",
+				"/metadata/annotations/kubescale~1up":       nil,
+			},
+		},
+		{
+			"replaces the opposite expiry",
+			map[string]string{"kubescale/up": "1h", "kubescale/down-until": "2025-06-03T10:00:00Z"},
+			map[string]interface{}{
+				"/metadata/annotations/kubescale~1up-until":   "2025-06-02T11:00:00Z",
+				"/metadata/annotations/kubescale~1up":         nil,
+				"/metadata/annotations/kubescale~1down-until": nil,
+			},
+		},
+		{
+			"expired expiry removed",
+			map[string]string{"kubescale/up-until": "2025-06-02T09:00:00+02:00"},
+			map[string]interface{}{"/metadata/annotations/kubescale~1up-until": nil},
+		},
+		{"passed down-until left to the controller", map[string]string{"kubescale/down-until": "2025-06-02T09:00:00Z"}, map[string]interface{}{}},
+		{"running expiry kept", map[string]string{"kubescale/up-until": "2025-06-02T11:00:00Z"}, map[string]interface{}{}},
+		{"nothing to resolve", map[string]string{"kubescale/uptime": "Mon-Fri 08:00-18:00"}, map[string]interface{}{}},
+		{"invalid left to the validator", map[string]string{"kubescale/up": "3 days"}, map[string]interface{}{}},
+	}
+
+	m := &DurationResolver{Now: func() time.Time { return now }}
+	for _, test := range tests {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    deployment(test.object),
+		}}
+		resp := m.Handle(context.Background(), req)
+		assert.True(t, resp.Allowed, "unexpected denial for test case: %s", test.name)
+
+		patches := map[string]interface{}{}
+		for _, p := range resp.Patches {
+			patches[p.Path] = p.Value
+		}
+		assert.Equal(t, test.patches, patches, "unexpected patches for test case: %s", test.name)
+	}
+}
+
+func TestDurationResolverNamespaceTimezone(t *testing.T) {
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	paris, _ := time.LoadLocation("Europe/Paris")
+	reader := fake.NewClientBuilder().WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "team-a",
+		Annotations: map[string]string{"kubescale/timezone": "Asia/Tokyo"},
+	}}).Build()
+
+	tests := []struct {
+		name      string
+		resolver  *DurationResolver
+		namespace string
+		object    map[string]string
+		until     string
+	}{
+		{"cluster default", &DurationResolver{Location: paris}, "team-a", map[string]string{"kubescale/up": "2h"}, "2025-06-02T14:00:00+02:00"},
+		{"namespace timezone", &DurationResolver{Reader: reader, Location: paris}, "team-a", map[string]string{"kubescale/up": "2h"}, "2025-06-02T21:00:00+09:00"},
+		{"namespace without timezone", &DurationResolver{Reader: reader, Location: paris}, "team-b", map[string]string{"kubescale/up": "2h"}, "2025-06-02T14:00:00+02:00"},
+		{"explicit timezone", &DurationResolver{Reader: reader}, "team-a", map[string]string{"kubescale/up": "2h UTC"}, "2025-06-02T12:00:00Z"},
+	}
+
+	for _, test := range tests {
+		test.resolver.Now = func() time.Time { return now }
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Namespace: test.namespace,
+			Object:    deployment(test.object),
+		}}
+		resp := test.resolver.Handle(context.Background(), req)
+		patches := map[string]interface{}{}
+		for _, p := range resp.Patches {
+			patches[p.Path] = p.Value
+		}
+		assert.Equal(t, test.until, patches["/metadata/annotations/kubescale~1up-until"], "unexpected expiry for test case: %s", test.name)
+	}
+}
@@ -20,22 +20,28 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	autoscalev1alpha1 "github.com/cicd-toolkit/kubescale/api/v1alpha1"
 	"github.com/cicd-toolkit/kubescale/internal/controller"
+	kubescalewebhook "github.com/cicd-toolkit/kubescale/internal/webhook"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -47,6 +53,8 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
+	utilruntime.Must(autoscalev1alpha1.AddToScheme(scheme))
+
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -56,6 +64,10 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var enableWebhooks bool
+	var presetConfigMap string
+	var defaultTimezone string
+	var calendarImportDir string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -67,6 +79,14 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the admission webhooks are served. They require a serving certificate in the webhook cert dir.")
+	flag.StringVar(&presetConfigMap, "preset-configmap", "",
+		"The namespace/name of the ConfigMap defining the schedule presets. Presets are disabled when empty.")
+	flag.StringVar(&defaultTimezone, "default-timezone", "UTC",
+		"The IANA timezone of windows without one, in namespaces without the kubescale/timezone annotation.")
+	flag.StringVar(&calendarImportDir, "calendar-import-dir", "",
+		"The directory ScaleCalendars import iCalendar files from with path. Path imports are disabled when empty.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -75,6 +95,12 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	location, err := time.LoadLocation(defaultTimezone)
+	if err != nil {
+		setupLog.Error(err, "invalid --default-timezone, expected an IANA name such as Europe/Paris", "value", defaultTimezone)
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -118,8 +144,21 @@ func main() {
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
 
+	var presetRef types.NamespacedName
+	var cacheOptions cache.Options
+	if presetConfigMap != "" {
+		namespace, name, ok := strings.Cut(presetConfigMap, "/")
+		if !ok {
+			setupLog.Error(nil, "invalid --preset-configmap, expected namespace/name", "value", presetConfigMap)
+			os.Exit(1)
+		}
+		presetRef = types.NamespacedName{Namespace: namespace, Name: name}
+		cacheOptions.ByObject = controller.PresetCache(presetRef)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  cacheOptions,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
@@ -142,13 +181,32 @@ func main() {
 		os.Exit(1)
 	}
 
+	presets := controller.NewPresetRegistry()
+	if presetConfigMap != "" {
+		if err = (&controller.PresetReconciler{
+			Client:    mgr.GetClient(),
+			Registry:  presets,
+			ConfigMap: presetRef,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Presets")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controller.ScalerReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Presets:   presets,
+		Location:  location,
+		APIReader: mgr.GetAPIReader(),
+		ImportDir: calendarImportDir,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Scaler")
 		os.Exit(1)
 	}
+	if enableWebhooks {
+		kubescalewebhook.SetupAnnotationWebhookWithManager(mgr, location)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {